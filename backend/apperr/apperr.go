@@ -0,0 +1,159 @@
+// Package apperr defines the typed errors returned by the API.
+// Every error carries a stable machine-readable code the app can branch on,
+// the HTTP status it maps to, a human readable message and optional field details.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Code string
+
+// Generic codes, one per HTTP status
+const (
+	CodeBadRequest      Code = "bad_request"
+	CodeValidation      Code = "validation_failed"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeForbidden       Code = "forbidden"
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeFileTooLarge    Code = "file_too_large"
+	CodeRateLimited     Code = "rate_limited"
+	CodeInternal        Code = "internal_error"
+	CodeUnsupportedFile Code = "unsupported_file"
+)
+
+// Specific codes for cases the app handles differently from the generic status
+const (
+	CodeTokenMissing       Code = "token_missing"
+	CodeTokenInvalid       Code = "token_invalid"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeEmailTaken         Code = "email_taken"
+	CodeAdminRequired      Code = "admin_required"
+	CodeSchoolRequired     Code = "school_required"
+	CodeChatBlocked        Code = "chat_blocked"
+	CodeEditWindowExpired  Code = "edit_window_expired"
+	CodeCorrectionRequired Code = "weight_correction_required"
+	CodeDepositCompleted   Code = "deposit_completed"
+	CodeSelfReview         Code = "self_review"
+	CodeCheckInInvalid     Code = "check_in_invalid"
+	CodeCheckInUsed        Code = "check_in_used"
+	CodeCheckInRequired    Code = "check_in_required"
+)
+
+// Fields maps request field names to what is wrong with them
+type Fields map[string]string
+
+// Params fills the {name} placeholders of a message. Messages are translated by their
+// template text first, so values such as limits never end up in the lookup key.
+type Params map[string]string
+
+// FieldParams holds the Params of Fields messages, by field name
+type FieldParams map[string]Params
+
+type Error struct {
+	Status      int
+	Code        Code
+	Message     string
+	Params      Params
+	Fields      Fields
+	FieldParams FieldParams
+	Err         error // Underlying cause, logged but never sent to the client
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	for name, value := range e.Params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode replaces the generic code with a more specific one
+func (e *Error) WithCode(code Code) *Error {
+	e.Code = code
+	return e
+}
+
+// WithParams sets the values of the message's {name} placeholders
+func (e *Error) WithParams(params Params) *Error {
+	e.Params = params
+	return e
+}
+
+// WithFieldParams sets the values of the {name} placeholders in field messages
+func (e *Error) WithFieldParams(params FieldParams) *Error {
+	e.FieldParams = params
+	return e
+}
+
+// Wrap records the underlying cause for the logs
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Validation reports field-level problems with the request
+func Validation(fields Fields) *Error {
+	e := New(http.StatusBadRequest, CodeValidation, "Validation failed")
+	e.Fields = fields
+	return e
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+func TooManyRequests(message string) *Error {
+	return New(http.StatusTooManyRequests, CodeRateLimited, message)
+}
+
+func Internal(message string) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, message)
+}
+
+// From converts any error into an *Error; unknown errors become a generic internal error
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal("Something went wrong").Wrap(err)
+}
+
+// Abort records the error for the error middleware and stops the handler chain
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,84 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Report binding errors by their JSON field names rather than Go struct field names
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// BindingFields turns a ShouldBind error into per-field messages and their placeholder values.
+// Errors that are not about a single field (malformed JSON) are reported under "body".
+func BindingFields(err error) (Fields, FieldParams) {
+	fields := Fields{}
+	params := FieldParams{}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		fields[typeError.Field] = "must be a {type}"
+		params[typeError.Field] = Params{"type": typeError.Type.String()}
+		return fields, params
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		fields["body"] = err.Error()
+		return fields, params
+	}
+
+	for _, fe := range validationErrors {
+		switch fe.Tag() {
+		case "required":
+			fields[fe.Field()] = "is required"
+		case "max":
+			fields[fe.Field()] = lengthMessage(fe.Kind(), "must be at most {max}")
+			params[fe.Field()] = Params{"max": fe.Param()}
+		case "min":
+			fields[fe.Field()] = lengthMessage(fe.Kind(), "must be at least {min}")
+			params[fe.Field()] = Params{"min": fe.Param()}
+		case "oneof":
+			fields[fe.Field()] = "must be one of: {values}"
+			params[fe.Field()] = Params{"values": strings.ReplaceAll(fe.Param(), " ", ", ")}
+		case "email":
+			fields[fe.Field()] = "must be a valid email address"
+		default:
+			fields[fe.Field()] = "is invalid"
+		}
+	}
+	return fields, params
+}
+
+// lengthMessage words a min or max failure by the field kind: strings count characters,
+// slices and maps count items, and numbers compare the value itself
+func lengthMessage(kind reflect.Kind, message string) string {
+	switch kind {
+	case reflect.String:
+		return message + " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return message + " items"
+	default:
+		return message
+	}
+}
+
+// Binding converts a ShouldBind error into a validation error
+func Binding(err error) *Error {
+	fields, params := BindingFields(err)
+	return Validation(fields).WithFieldParams(params)
+}
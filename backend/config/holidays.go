@@ -0,0 +1,37 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"time"
+)
+
+// Holidays returns the dates (YYYY-MM-DD) on which no pickups are scheduled.
+// Dates come from HOLIDAYS, e.g. "2025-01-01,2025-03-31", and from HOLIDAYS_FILE,
+// a text file with one date per line where anything after # is a comment.
+func Holidays() map[string]bool {
+	holidays := map[string]bool{}
+	add := func(value string) {
+		value = strings.TrimSpace(value)
+		if _, err := time.Parse("2006-01-02", value); err == nil {
+			holidays[value] = true
+		}
+	}
+
+	for _, date := range strings.Split(os.Getenv("HOLIDAYS"), ",") {
+		add(date)
+	}
+
+	if path := os.Getenv("HOLIDAYS_FILE"); path != "" {
+		if file, err := os.Open(path); err == nil {
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				line, _, _ := strings.Cut(scanner.Text(), "#")
+				add(line)
+			}
+		}
+	}
+	return holidays
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Default kg CO2-equivalent avoided per kg of waste collected instead of landfilled
+var defaultEmissionFactors = map[string]float64{
+	"Sampah Organik":   0.5,
+	"Sampah Anorganik": 1.0,
+}
+
+// DefaultEmissionFactor is used for waste types without a configured factor
+const DefaultEmissionFactor = 0.5
+
+// EmissionFactors returns kg CO2e avoided per kg by waste type.
+// EMISSION_FACTORS overrides the defaults, e.g. "Sampah Organik=0.5,Sampah Anorganik=1.2".
+func EmissionFactors() map[string]float64 {
+	factors := make(map[string]float64, len(defaultEmissionFactors))
+	for k, v := range defaultEmissionFactors {
+		factors[k] = v
+	}
+
+	for _, pair := range strings.Split(os.Getenv("EMISSION_FACTORS"), ",") {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		factor, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || factor < 0 {
+			continue
+		}
+		factors[strings.TrimSpace(name)] = factor
+	}
+	return factors
+}
+
+// PointsPerKg returns how many points a school earns per kg of completed deposits, from POINTS_PER_KG (default 10)
+func PointsPerKg() float64 {
+	points, err := strconv.ParseFloat(os.Getenv("POINTS_PER_KG"), 64)
+	if err != nil || points < 0 {
+		return 10
+	}
+	return points
+}
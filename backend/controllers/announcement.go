@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Number of notification rows inserted per statement when broadcasting
+const announcementBatchSize = 500
+
+// How long an announcement may stay in "sending" without progress before the scheduler
+// assumes the sender died (e.g. a restart) and sends the rest
+const announcementClaimTimeout = 10 * time.Minute
+
+type AnnouncementInput struct {
+	Title         string   `json:"title" binding:"required"`
+	Message       string   `json:"message" binding:"required"`
+	TargetType    string   `json:"target_type" binding:"required,oneof=all role schools"`
+	TargetRole    string   `json:"target_role" binding:"omitempty,oneof=user admin"`
+	TargetSchools []string `json:"target_schools"` // School IDs
+	ScheduledAt   string   `json:"scheduled_at"`   // RFC3339, empty to send immediately
+}
+
+type announcementStats struct {
+	AnnouncementID uuid.UUID
+	Delivered      int64
+	Read           int64
+}
+
+// CreateAnnouncement creates a broadcast announcement and sends it now or at scheduled_at (admin only)
+func CreateAnnouncement(c *gin.Context) {
+	adminUserID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var input AnnouncementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	if input.TargetType == "role" && input.TargetRole == "" {
+		apperr.Abort(c, apperr.BadRequest("target_role is required for role announcements"))
+		return
+	}
+	if input.TargetType == "schools" {
+		if len(input.TargetSchools) == 0 {
+			apperr.Abort(c, apperr.BadRequest("target_schools is required for school announcements"))
+			return
+		}
+		var found int64
+		if err := config.DB.Model(&models.School{}).Where("id IN ?", input.TargetSchools).Count(&found).Error; err != nil || int(found) != len(input.TargetSchools) {
+			apperr.Abort(c, apperr.BadRequest("target_schools must be existing school IDs"))
+			return
+		}
+	}
+
+	now := time.Now().In(jakartaLoc)
+	scheduledAt := now
+	if input.ScheduledAt != "" {
+		parsed, err := time.Parse(time.RFC3339, input.ScheduledAt)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid scheduled_at. Use RFC3339, e.g. 2024-12-24T08:00:00+07:00"))
+			return
+		}
+		if parsed.After(now) {
+			scheduledAt = parsed.In(jakartaLoc)
+		}
+	}
+
+	announcement := models.Announcement{
+		Title:       input.Title,
+		Message:     input.Message,
+		TargetType:  input.TargetType,
+		Status:      "scheduled",
+		ScheduledAt: scheduledAt,
+		CreatedBy:   adminUserID.(uuid.UUID),
+	}
+	switch input.TargetType {
+	case "role":
+		announcement.TargetRole = input.TargetRole
+	case "schools":
+		announcement.TargetSchools = input.TargetSchools
+	}
+
+	if err := config.DB.Create(&announcement).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to create announcement").Wrap(err))
+		return
+	}
+	recordAudit(c, "announcement.create", "announcement", announcement.ID.String(), nil, map[string]interface{}{
+		"title":        announcement.Title,
+		"target_type":  announcement.TargetType,
+		"scheduled_at": announcement.ScheduledAt,
+	})
+
+	if !scheduledAt.After(now) {
+		go sendAnnouncement(announcement.ID)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Announcement created successfully",
+		"announcement": announcement,
+	})
+}
+
+// GetAnnouncements returns all announcements with delivery and read statistics (admin only)
+func GetAnnouncements(c *gin.Context) {
+	var announcements []models.Announcement
+	if err := config.DB.Preload("Creator").Order("scheduled_at DESC").Find(&announcements).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch announcements").Wrap(err))
+		return
+	}
+
+	var stats []announcementStats
+	config.DB.Model(&models.Notification{}).
+		Select("announcement_id, COUNT(*) AS delivered, COUNT(*) FILTER (WHERE is_read) AS read").
+		Where("announcement_id IS NOT NULL").
+		Group("announcement_id").
+		Scan(&stats)
+
+	statsMap := make(map[uuid.UUID]announcementStats)
+	for _, s := range stats {
+		statsMap[s.AnnouncementID] = s
+	}
+
+	result := make([]gin.H, 0, len(announcements))
+	for _, a := range announcements {
+		result = append(result, gin.H{
+			"announcement": a,
+			"delivered":    statsMap[a.ID].Delivered,
+			"read":         statsMap[a.ID].Read,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcements": result})
+}
+
+// GetAnnouncementByID returns a single announcement with delivery and read statistics (admin only)
+func GetAnnouncementByID(c *gin.Context) {
+	var announcement models.Announcement
+	if err := config.DB.Preload("Creator").Where("id = ?", c.Param("id")).First(&announcement).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Announcement not found"))
+		return
+	}
+
+	var stats announcementStats
+	config.DB.Model(&models.Notification{}).
+		Select("COUNT(*) AS delivered, COUNT(*) FILTER (WHERE is_read) AS read").
+		Where("announcement_id = ?", announcement.ID).
+		Scan(&stats)
+
+	c.JSON(http.StatusOK, gin.H{
+		"announcement": announcement,
+		"delivered":    stats.Delivered,
+		"read":         stats.Read,
+	})
+}
+
+// CancelAnnouncement deletes an announcement that has not been sent yet (admin only)
+func CancelAnnouncement(c *gin.Context) {
+	result := config.DB.Where("id = ? AND status = ?", c.Param("id"), "scheduled").Delete(&models.Announcement{})
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to cancel announcement"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperr.Abort(c, apperr.NotFound("Scheduled announcement not found"))
+		return
+	}
+	recordAudit(c, "announcement.cancel", "announcement", c.Param("id"), map[string]interface{}{"status": "scheduled"}, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement cancelled"})
+}
+
+// StartAnnouncementScheduler periodically sends announcements whose scheduled time has passed
+func StartAnnouncementScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			var due []models.Announcement
+			stale := time.Now().Add(-announcementClaimTimeout)
+			if err := config.DB.Select("id").
+				Where("(status = ? AND scheduled_at <= ?) OR (status = ? AND (claimed_at IS NULL OR claimed_at < ?))", "scheduled", time.Now(), "sending", stale).
+				Find(&due).Error; err != nil {
+				log.Println("Announcement scheduler:", err)
+				continue
+			}
+			for _, a := range due {
+				sendAnnouncement(a.ID)
+			}
+		}
+	}()
+}
+
+// sendAnnouncement creates notifications for every targeted user in batches.
+// The status transition to "sending" acts as a lock, refreshed after every batch. A stale lock can be
+// taken over; users who already have the announcement are skipped, so nobody gets it twice.
+func sendAnnouncement(id uuid.UUID) {
+	now := time.Now()
+	claim := config.DB.Model(&models.Announcement{}).
+		Where("id = ? AND (status = ? OR (status = ? AND (claimed_at IS NULL OR claimed_at < ?)))",
+			id, "scheduled", "sending", now.Add(-announcementClaimTimeout)).
+		Updates(map[string]interface{}{"status": "sending", "claimed_at": now})
+	if claim.Error != nil || claim.RowsAffected == 0 {
+		return
+	}
+
+	var announcement models.Announcement
+	if err := config.DB.Where("id = ?", id).First(&announcement).Error; err != nil {
+		log.Println("Failed to load announcement:", err)
+		return
+	}
+
+	query := config.DB.Model(&models.User{})
+	switch announcement.TargetType {
+	case "role":
+		query = query.Where("role = ?", announcement.TargetRole)
+	case "schools":
+		query = query.Where("school_id IN ?", announcement.TargetSchools)
+	}
+
+	// Users who switched off in-app announcements
+	optedOut := config.DB.Model(&models.NotificationPreference{}).
+		Select("user_id").
+		Where("category = ? AND channel = ? AND enabled = ?", "announcement", "in_app", false)
+	query = query.Where("id NOT IN (?)", optedOut)
+
+	// Users reached by an earlier, interrupted attempt
+	var delivered int64
+	config.DB.Model(&models.Notification{}).Where("announcement_id = ?", announcement.ID).Count(&delivered)
+	if delivered > 0 {
+		notified := config.DB.Model(&models.Notification{}).Select("user_id").Where("announcement_id = ?", announcement.ID)
+		query = query.Where("id NOT IN (?)", notified)
+	}
+
+	recipients := int(delivered)
+	var users []models.User
+	err := query.FindInBatches(&users, announcementBatchSize, func(tx *gorm.DB, batch int) error {
+		notifications := make([]models.Notification, 0, len(users))
+		for _, u := range users {
+			notifications = append(notifications, models.Notification{
+				UserID:         u.ID,
+				AnnouncementID: &announcement.ID,
+				Title:          announcement.Title,
+				Message:        announcement.Message,
+				Type:           "announcement",
+			})
+		}
+		if err := config.DB.Create(&notifications).Error; err != nil {
+			return err
+		}
+		recipients += len(notifications)
+
+		if len(notificationSenders) > 0 {
+			for i, u := range users {
+				sendExternalNotification(u, notifications[i])
+			}
+		}
+
+		config.DB.Model(&models.Announcement{}).Where("id = ?", announcement.ID).Update("claimed_at", time.Now())
+		return nil
+	}).Error
+
+	status := "sent"
+	if err != nil {
+		log.Printf("Failed to send announcement %s: %v", announcement.ID, err)
+		status = "failed"
+	}
+
+	sentAt := time.Now().In(jakartaLoc)
+	config.DB.Model(&announcement).Updates(map[string]interface{}{
+		"status":          status,
+		"sent_at":         &sentAt,
+		"recipient_count": recipients,
+	})
+}
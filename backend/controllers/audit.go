@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"log"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// auditDiff keeps only the keys whose value differs between before and after
+func auditDiff(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	for key, old := range before {
+		if _, ok := after[key]; !ok {
+			changedBefore[key] = old
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+// recordAudit appends an audit entry for a change made by the authenticated user.
+// Updates that changed nothing are not recorded. A failed write is logged, it never fails the request.
+func recordAudit(c *gin.Context, action, targetType, targetID string, before, after map[string]interface{}) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		return
+	}
+
+	if before != nil && after != nil {
+		before, after = auditDiff(before, after)
+		if len(after) == 0 && len(before) == 0 {
+			return
+		}
+	}
+
+	entry := models.AuditLog{
+		ActorID:    actorID.(uuid.UUID),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		RequestID:  c.GetString("request_id"),
+	}
+	if err := config.DB.Create(&entry).Error; err != nil {
+		log.Printf("Audit: failed to record %s on %s %s: %v", action, targetType, targetID, err)
+	}
+}
+
+// depositAuditFields is the part of a deposit tracked in the audit log
+func depositAuditFields(d models.WasteDeposit) map[string]interface{} {
+	fields := map[string]interface{}{
+		"status":    d.Status,
+		"weight":    nil,
+		"picker_id": nil,
+	}
+	if d.Weight != nil {
+		fields["weight"] = *d.Weight
+	}
+	if d.PickerID != nil {
+		fields["picker_id"] = d.PickerID.String()
+	}
+	return fields
+}
+
+// schoolAuditFields is the part of a school tracked in the audit log
+func schoolAuditFields(s models.School) map[string]interface{} {
+	fields := map[string]interface{}{
+		"name":          s.Name,
+		"npsn":          nil,
+		"address":       s.Address,
+		"contact_name":  s.ContactName,
+		"contact_phone": s.ContactPhone,
+		"latitude":      nil,
+		"longitude":     nil,
+	}
+	if s.NPSN != nil {
+		fields["npsn"] = *s.NPSN
+	}
+	if s.Latitude != nil {
+		fields["latitude"] = *s.Latitude
+	}
+	if s.Longitude != nil {
+		fields["longitude"] = *s.Longitude
+	}
+	return fields
+}
+
+// GetAuditLogs lists audit entries, newest first, filtered by ?actor_id=, ?action=, ?target_type=,
+// ?target_id= and ?from=/?to= (YYYY-MM-DD), with ?page= and ?limit= (admin only)
+func GetAuditLogs(c *gin.Context) {
+	page, limit, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.AuditLog{})
+	if actorIDStr := c.Query("actor_id"); actorIDStr != "" {
+		actorID, err := uuid.Parse(actorIDStr)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid actor_id"))
+			return
+		}
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.ParseInLocation("2006-01-02", fromStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid from date. Use YYYY-MM-DD"))
+			return
+		}
+		query = query.Where("created_at >= ?", from)
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.ParseInLocation("2006-01-02", toStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid to date. Use YYYY-MM-DD"))
+			return
+		}
+		query = query.Where("created_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch audit log").Wrap(err))
+		return
+	}
+
+	var entries []models.AuditLog
+	if err := query.Preload("Actor").Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&entries).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch audit log").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
@@ -1,13 +1,13 @@
 package controllers
 
 import (
+	"backend-api/apperr"
 	"backend-api/config"
+	"backend-api/i18n"
 	"backend-api/models"
 	"backend-api/utils"
-	"fmt"
 	"net/http"
-	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,6 +17,7 @@ type RegisterInput struct {
 	Name       string `json:"name" binding:"required"`
 	Email      string `json:"email" binding:"required,email"`
 	Password   string `json:"password" binding:"required,min=6"`
+	SchoolID   string `json:"school_id"`
 	SchoolName string `json:"school_name"`
 }
 
@@ -28,38 +29,41 @@ type LoginInput struct {
 func Register(c *gin.Context) {
 	var input RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperr.Abort(c, apperr.Binding(err))
 		return
 	}
 
 	var existingUser models.User
 	if err := config.DB.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already exists"})
+		apperr.Abort(c, apperr.BadRequest("Email already exists").WithCode(apperr.CodeEmailTaken))
 		return
 	}
 
 	hashedPassword, err := utils.HashPassword(input.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		apperr.Abort(c, apperr.Internal("Failed to hash password").Wrap(err))
 		return
 	}
 
 	user := models.User{
-		Name:       input.Name,
-		Email:      input.Email,
-		Password:   hashedPassword,
-		SchoolName: input.SchoolName,
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: hashedPassword,
+	}
+	if err := setUserSchool(&user, input.SchoolID, input.SchoolName); err != nil {
+		apperr.Abort(c, apperr.BadRequest("School not found"))
+		return
 	}
 
 	if err := config.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		apperr.Abort(c, apperr.Internal("Failed to create user").Wrap(err))
 		return
 	}
 
 	// Generate token and log user in directly
 	token, err := utils.GenerateToken(user.ID, user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		apperr.Abort(c, apperr.Internal("Failed to generate token").Wrap(err))
 		return
 	}
 
@@ -72,30 +76,30 @@ func Register(c *gin.Context) {
 func Login(c *gin.Context) {
 	var input LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperr.Abort(c, apperr.Binding(err))
 		return
 	}
 
 	var user models.User
 	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		apperr.Abort(c, apperr.Unauthorized("Invalid email or password").WithCode(apperr.CodeInvalidCredentials))
 		return
 	}
 
 	// Check if user registered via Google (no password)
 	if user.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This account was created with Google. Please use 'Sign in with Google' button"})
+		apperr.Abort(c, apperr.BadRequest("This account was created with Google. Please use 'Sign in with Google' button"))
 		return
 	}
 
 	if !utils.CheckPassword(input.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		apperr.Abort(c, apperr.Unauthorized("Invalid email or password").WithCode(apperr.CodeInvalidCredentials))
 		return
 	}
 
 	token, err := utils.GenerateToken(user.ID, user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		apperr.Abort(c, apperr.Internal("Failed to generate token").Wrap(err))
 		return
 	}
 
@@ -108,13 +112,13 @@ func Login(c *gin.Context) {
 func GetMe(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var user models.User
 	if err := config.DB.Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		apperr.Abort(c, apperr.NotFound("User not found"))
 		return
 	}
 
@@ -123,12 +127,18 @@ func GetMe(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
-			"id":           user.ID,
-			"name":         user.Name,
-			"email":        user.Email,
-			"role":         user.Role,
-			"picture":      user.Picture,
-			"school_name": user.SchoolName,
+			"id":                 user.ID,
+			"name":               user.Name,
+			"email":              user.Email,
+			"role":               user.Role,
+			"picture":            user.Picture,
+			"picture_thumbnails": user.PictureThumbnails,
+			"school_id":          user.SchoolID,
+			"school_name":        user.SchoolName,
+			"school_role":        user.SchoolRole,
+			"pending_school_id":  user.PendingSchoolID,
+			"pending_school":     pendingSchoolName(user),
+			"locale":             user.Locale,
 		},
 	})
 }
@@ -136,13 +146,13 @@ func GetMe(c *gin.Context) {
 func UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var user models.User
 	if err := config.DB.Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		apperr.Abort(c, apperr.NotFound("User not found"))
 		return
 	}
 
@@ -151,50 +161,69 @@ func UpdateProfile(c *gin.Context) {
 		user.Name = name
 	}
 
-	// Update school_name if provided
-	if schoolName := c.PostForm("school_name"); schoolName != "" {
-		user.SchoolName = schoolName
+	// A school_id or school_name asks to join that school, or for a new school when the name is unknown;
+	// the user stays in their current school until a coordinator or admin approves
+	if schoolID, schoolName := c.PostForm("school_id"), c.PostForm("school_name"); schoolID != "" || schoolName != "" {
+		if err := setUserSchool(&user, schoolID, schoolName); err != nil {
+			apperr.Abort(c, apperr.BadRequest("School not found"))
+			return
+		}
 	}
 
-	// Handle picture upload
-	file, err := c.FormFile("picture")
-	if err == nil {
-		// Create uploads directory if not exists
-		uploadDir := "uploads/profiles"
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+	// Update preferred language if provided
+	if locale := c.PostForm("locale"); locale != "" {
+		if !i18n.Supported(locale) {
+			apperr.Abort(c, apperr.Validation(apperr.Fields{"locale": "must be one of: {values}"}).
+				WithFieldParams(apperr.FieldParams{"locale": {"values": strings.Join(i18n.Locales, ", ")}}))
 			return
 		}
+		user.Locale = locale
+	}
 
-		// Generate unique filename
-		ext := filepath.Ext(file.Filename)
-		filename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-		filePath := filepath.Join(uploadDir, filename)
+	oldPicture, oldThumbnails := user.Picture, user.PictureThumbnails
 
-		// Save file
-		if err := c.SaveUploadedFile(file, filePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save picture"})
+	// Handle picture upload
+	file, err := c.FormFile("picture")
+	if err == nil {
+		pictureURL, thumbnails, err := saveUploadedImage(c, file, "profiles", uuid.New().String(), true)
+		if err != nil {
+			respondUploadError(c, err, "Failed to save picture")
 			return
 		}
 
-		user.Picture = "/" + filePath
+		user.Picture = pictureURL
+		user.PictureThumbnails = thumbnails
 	}
 
 	// Save changes
 	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		if user.Picture != oldPicture {
+			deleteStoredFiles(user.Picture, user.PictureThumbnails)
+		}
+		apperr.Abort(c, apperr.Internal("Failed to update profile"))
 		return
 	}
 
+	// The old picture is only removed once the new one is saved
+	if user.Picture != oldPicture {
+		deleteStoredFiles(oldPicture, oldThumbnails)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile updated successfully",
 		"user": gin.H{
-			"id":           user.ID,
-			"name":         user.Name,
-			"email":        user.Email,
-			"role":         user.Role,
-			"picture":      user.Picture,
-			"school_name": user.SchoolName,
+			"id":                 user.ID,
+			"name":               user.Name,
+			"email":              user.Email,
+			"role":               user.Role,
+			"picture":            user.Picture,
+			"picture_thumbnails": user.PictureThumbnails,
+			"school_id":          user.SchoolID,
+			"school_name":        user.SchoolName,
+			"school_role":        user.SchoolRole,
+			"pending_school_id":  user.PendingSchoolID,
+			"pending_school":     pendingSchoolName(user),
+			"locale":             user.Locale,
 		},
 	})
 }
@@ -1,10 +1,16 @@
 package controllers
 
 import (
+	"backend-api/apperr"
 	"backend-api/config"
 	"backend-api/models"
+	"errors"
+	"io"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,10 +18,16 @@ import (
 
 var jakartaLoc, _ = time.LoadLocation("Asia/Jakarta")
 
+const (
+	defaultMessagePageSize = 50
+	maxMessagePageSize     = 100
+	maxMessageLength       = 2000
+)
+
 func GetChatList(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
@@ -105,47 +117,263 @@ func GetChatList(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"chats": chatUsers})
 }
 
-// GetMessages returns messages between current user and another user
+// GetMessages returns a page of messages between current user and another user.
+// Use ?before=<message_id> to load older messages and ?after=<message_id> to load newer ones.
 func GetMessages(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	otherUserID := c.Param("user_id")
 	otherUUID, err := uuid.Parse(otherUserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
 		return
 	}
 
 	currentUserID := userID.(uuid.UUID)
 
-	var messages []models.ChatMessage
-	config.DB.Where("(sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?)",
+	limit := defaultMessagePageSize
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			apperr.Abort(c, apperr.BadRequest("Invalid limit"))
+			return
+		}
+		if parsed > maxMessagePageSize {
+			parsed = maxMessagePageSize
+		}
+		limit = parsed
+	}
+
+	beforeID := c.Query("before")
+	afterID := c.Query("after")
+	if beforeID != "" && afterID != "" {
+		apperr.Abort(c, apperr.BadRequest("Use either before or after, not both"))
+		return
+	}
+
+	query := config.DB.Where("((sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?))",
 		currentUserID, otherUUID, otherUUID, currentUserID).
 		Preload("Sender").
-		Order("created_at ASC").
-		Find(&messages)
+		Preload("Deposit")
 
-	// Mark messages as read
-	config.DB.Model(&models.ChatMessage{}).
-		Where("sender_id = ? AND receiver_id = ? AND is_read = ?", otherUUID, currentUserID, false).
-		Update("is_read", true)
+	ascending := false
+	if beforeID != "" || afterID != "" {
+		cursorID := beforeID
+		if afterID != "" {
+			cursorID = afterID
+		}
+
+		var cursor models.ChatMessage
+		if err := config.DB.Where("id = ? AND ((sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?))",
+			cursorID, currentUserID, otherUUID, otherUUID, currentUserID).First(&cursor).Error; err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid message cursor"))
+			return
+		}
+
+		if afterID != "" {
+			query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+			ascending = true
+		} else {
+			query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+	}
+
+	if ascending {
+		query = query.Order("created_at ASC, id ASC")
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	// Fetch one extra row to know whether another page exists
+	var messages []models.ChatMessage
+	if err := query.Limit(limit + 1).Find(&messages).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch messages").Wrap(err))
+		return
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	// Always return messages oldest first
+	if !ascending {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
 
 	// Get other user info
 	var otherUser models.User
 	config.DB.Where("id = ?", otherUUID).First(&otherUser)
 
-	c.JSON(http.StatusOK, gin.H{"messages": messages, "user": otherUser})
+	c.JSON(http.StatusOK, gin.H{"messages": withSignedAttachments(c.Request.Context(), messages), "user": otherUser, "has_more": hasMore})
+}
+
+// MarkMessagesAsRead marks messages from another user as read up to and including message_id.
+// If message_id is omitted, every message in the conversation is marked as read.
+func MarkMessagesAsRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	otherUUID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
+		return
+	}
+
+	var input struct {
+		MessageID string `json:"message_id"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	currentUserID := userID.(uuid.UUID)
+
+	query := config.DB.Model(&models.ChatMessage{}).
+		Where("sender_id = ? AND receiver_id = ? AND is_read = ?", otherUUID, currentUserID, false)
+
+	if input.MessageID != "" {
+		var upTo models.ChatMessage
+		if err := config.DB.Where("id = ? AND ((sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?))",
+			input.MessageID, currentUserID, otherUUID, otherUUID, currentUserID).First(&upTo).Error; err != nil {
+			apperr.Abort(c, apperr.NotFound("Message not found"))
+			return
+		}
+		query = query.Where("(created_at, id) <= (?, ?)", upTo.CreatedAt, upTo.ID)
+	}
+
+	result := query.Update("is_read", true)
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update messages"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Messages marked as read", "updated": result.RowsAffected})
+}
+
+// EditMessage updates the text of the current user's own message within the edit window
+func EditMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var input struct {
+		Message string `json:"message" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	if utf8.RuneCountInString(input.Message) > maxMessageLength {
+		apperr.Abort(c, apperr.BadRequest("Message must be at most {max} characters").WithParams(apperr.Params{"max": strconv.Itoa(maxMessageLength)}))
+		return
+	}
+
+	var message models.ChatMessage
+	if err := config.DB.Where("id = ? AND sender_id = ?", c.Param("id"), userID.(uuid.UUID)).First(&message).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Message not found"))
+		return
+	}
+
+	if message.DeletedAt != nil {
+		apperr.Abort(c, apperr.BadRequest("Message has been deleted"))
+		return
+	}
+
+	if time.Since(message.CreatedAt) > chatEditWindow() {
+		apperr.Abort(c, apperr.Forbidden("Message can no longer be edited").WithCode(apperr.CodeEditWindowExpired))
+		return
+	}
+
+	now := time.Now().In(jakartaLoc)
+	message.Message = input.Message
+	message.EditedAt = &now
+	if err := config.DB.Model(&message).Updates(map[string]interface{}{
+		"message":   message.Message,
+		"edited_at": message.EditedAt,
+	}).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to edit message").Wrap(err))
+		return
+	}
+
+	config.DB.Preload("Sender").Preload("Deposit").First(&message, message.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": withSignedAttachment(c.Request.Context(), message)})
+}
+
+// DeleteMessage soft deletes the current user's own message within the edit window.
+// The message stays in the conversation with an empty body and deleted_at set.
+func DeleteMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var message models.ChatMessage
+	if err := config.DB.Where("id = ? AND sender_id = ?", c.Param("id"), userID.(uuid.UUID)).First(&message).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Message not found"))
+		return
+	}
+
+	if message.DeletedAt != nil {
+		c.JSON(http.StatusOK, gin.H{"message": withSignedAttachment(c.Request.Context(), message)})
+		return
+	}
+
+	if time.Since(message.CreatedAt) > chatEditWindow() {
+		apperr.Abort(c, apperr.Forbidden("Message can no longer be deleted").WithCode(apperr.CodeEditWindowExpired))
+		return
+	}
+
+	now := time.Now().In(jakartaLoc)
+	oldAttachment := message.Attachment
+	message.Message = ""
+	message.Attachment = ""
+	message.DeletedAt = &now
+	if err := config.DB.Model(&message).Updates(map[string]interface{}{
+		"message":    message.Message,
+		"attachment": message.Attachment,
+		"deleted_at": message.DeletedAt,
+	}).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to delete message").Wrap(err))
+		return
+	}
+
+	deleteStoredFiles(oldAttachment, nil)
+
+	config.DB.Preload("Sender").Preload("Deposit").First(&message, message.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": withSignedAttachment(c.Request.Context(), message)})
+}
+
+// chatEditWindow returns how long after sending a message can still be edited or deleted
+func chatEditWindow() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("CHAT_EDIT_WINDOW_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
 }
 
 // GetUnreadCount returns total unread messages for current user
 func GetUnreadCount(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
@@ -159,41 +387,109 @@ func GetUnreadCount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"unread_count": unreadCount})
 }
 
-// SendMessage sends a message to another user
+// SendMessage sends a message to another user.
+// Accepts JSON for text and deposit messages, or multipart form with an "attachment" file for images.
 func SendMessage(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	receiverID := c.Param("user_id")
 	receiverUUID, err := uuid.Parse(receiverID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
 		return
 	}
 
 	var input struct {
-		Message string `json:"message" binding:"required"`
+		Type      string `json:"type" form:"type"`
+		Message   string `json:"message" form:"message"`
+		DepositID string `json:"deposit_id" form:"deposit_id"`
 	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBind(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	if input.Type == "" {
+		input.Type = "text"
+	}
+
+	if utf8.RuneCountInString(input.Message) > maxMessageLength {
+		apperr.Abort(c, apperr.BadRequest("Message must be at most {max} characters").WithParams(apperr.Params{"max": strconv.Itoa(maxMessageLength)}))
+		return
+	}
+
+	senderID := userID.(uuid.UUID)
+	if err := checkChatPolicy(senderID, receiverUUID); err != nil {
+		apperr.Abort(c, err)
 		return
 	}
 
 	message := models.ChatMessage{
-		SenderID:   userID.(uuid.UUID),
+		SenderID:   senderID,
 		ReceiverID: receiverUUID,
+		Type:       input.Type,
 		Message:    input.Message,
 	}
 
+	switch input.Type {
+	case "text":
+		if input.Message == "" {
+			apperr.Abort(c, apperr.BadRequest("Message is required"))
+			return
+		}
+	case "image":
+		file, err := c.FormFile("attachment")
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("No file uploaded"))
+			return
+		}
+
+		attachmentURL, _, err := saveUploadedImage(c, file, "chat", uuid.New().String()+"_"+time.Now().Format("20060102150405"), false)
+		if err != nil {
+			respondUploadError(c, err, "Failed to save file")
+			return
+		}
+
+		message.Attachment = attachmentURL
+	case "deposit":
+		depositUUID, err := uuid.Parse(input.DepositID)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid deposit_id"))
+			return
+		}
+
+		// The referenced deposit must belong to one of the two participants
+		var deposit models.WasteDeposit
+		if err := config.DB.Where("id = ? AND user_id IN ?", depositUUID, []uuid.UUID{senderID, receiverUUID}).First(&deposit).Error; err != nil {
+			apperr.Abort(c, apperr.NotFound("Deposit not found"))
+			return
+		}
+		message.DepositID = &deposit.ID
+	default:
+		apperr.Abort(c, apperr.BadRequest("Invalid type. Must be: text, image, or deposit"))
+		return
+	}
+
 	if err := config.DB.Create(&message).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+		deleteStoredFiles(message.Attachment, nil)
+		apperr.Abort(c, apperr.Internal("Failed to send message"))
 		return
 	}
 
-	config.DB.Preload("Sender").First(&message, message.ID)
+	config.DB.Preload("Sender").Preload("Deposit").First(&message, message.ID)
+
+	templateKey := "chat.new_message"
+	if message.Type == "image" {
+		templateKey = "chat.new_photo"
+	}
+	CreateNotification(receiverUUID, message.DepositID, templateKey, map[string]string{
+		"sender": message.Sender.Name,
+		"text":   message.Message,
+	}, "chat")
 
-	c.JSON(http.StatusCreated, gin.H{"message": message})
+	c.JSON(http.StatusCreated, gin.H{"message": withSignedAttachment(c.Request.Context(), message)})
 }
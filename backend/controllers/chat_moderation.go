@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// checkChatPolicy decides whether sender may message receiver.
+// Returns nil when allowed, otherwise the error to respond with.
+func checkChatPolicy(senderID, receiverID uuid.UUID) *apperr.Error {
+	if senderID == receiverID {
+		return apperr.BadRequest("Cannot send a message to yourself")
+	}
+
+	var sender models.User
+	if err := config.DB.Where("id = ?", senderID).First(&sender).Error; err != nil {
+		return apperr.Unauthorized("User not found")
+	}
+
+	var receiver models.User
+	if err := config.DB.Where("id = ?", receiverID).First(&receiver).Error; err != nil {
+		return apperr.NotFound("Recipient not found")
+	}
+
+	if sender.ChatBlocked {
+		return apperr.Forbidden("You are not allowed to send messages").WithCode(apperr.CodeChatBlocked)
+	}
+
+	// Regular users may only message admins, or reply in a conversation that already exists
+	if sender.Role != "admin" && receiver.Role != "admin" {
+		var existing int64
+		config.DB.Model(&models.ChatMessage{}).
+			Where("sender_id = ? AND receiver_id = ?", receiverID, senderID).
+			Count(&existing)
+		if existing == 0 {
+			return apperr.Forbidden("You can only send messages to admins")
+		}
+	}
+
+	var blocked int64
+	config.DB.Model(&models.ChatBlock{}).
+		Where("blocker_id = ? AND blocked_id = ?", receiverID, senderID).
+		Count(&blocked)
+	if blocked > 0 {
+		return apperr.Forbidden("This user is not accepting your messages").WithCode(apperr.CodeChatBlocked)
+	}
+
+	return nil
+}
+
+// BlockUser stops another user from sending messages to the current user
+func BlockUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	blockedUUID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
+		return
+	}
+
+	currentUserID := userID.(uuid.UUID)
+	if blockedUUID == currentUserID {
+		apperr.Abort(c, apperr.BadRequest("Cannot block yourself"))
+		return
+	}
+
+	var blockedUser models.User
+	if err := config.DB.Where("id = ?", blockedUUID).First(&blockedUser).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("User not found"))
+		return
+	}
+
+	block := models.ChatBlock{
+		BlockerID: currentUserID,
+		BlockedID: blockedUUID,
+	}
+	if err := config.DB.Where("blocker_id = ? AND blocked_id = ?", currentUserID, blockedUUID).FirstOrCreate(&block).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to block user").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+// UnblockUser removes a block previously created by the current user
+func UnblockUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	blockedUUID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
+		return
+	}
+
+	if err := config.DB.Where("blocker_id = ? AND blocked_id = ?", userID.(uuid.UUID), blockedUUID).Delete(&models.ChatBlock{}).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to unblock user").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
+}
+
+// ReportUser reports another user, optionally pointing at one of their messages
+func ReportUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	reportedUUID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
+		return
+	}
+
+	var input struct {
+		Reason    string `json:"reason" binding:"required,max=1000"`
+		MessageID string `json:"message_id"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	currentUserID := userID.(uuid.UUID)
+
+	var reportedUser models.User
+	if err := config.DB.Where("id = ?", reportedUUID).First(&reportedUser).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("User not found"))
+		return
+	}
+
+	report := models.ChatReport{
+		ReporterID:     currentUserID,
+		ReportedUserID: reportedUUID,
+		Reason:         input.Reason,
+		Status:         "pending",
+	}
+
+	if input.MessageID != "" {
+		// Only messages the reported user sent to the reporter can be attached
+		var message models.ChatMessage
+		if err := config.DB.Where("id = ? AND sender_id = ? AND receiver_id = ?", input.MessageID, reportedUUID, currentUserID).First(&message).Error; err != nil {
+			apperr.Abort(c, apperr.NotFound("Message not found"))
+			return
+		}
+		report.MessageID = &message.ID
+	}
+
+	if err := config.DB.Create(&report).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to create report").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Report submitted",
+		"report":  report,
+	})
+}
+
+// GetChatReports returns chat reports, optionally filtered by ?status= (admin only)
+func GetChatReports(c *gin.Context) {
+	query := config.DB.Preload("Reporter").Preload("ReportedUser").Preload("Message").Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var reports []models.ChatReport
+	if err := query.Find(&reports).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch reports").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+// ResolveChatReport resolves or dismisses a chat report and can block the reported user from chatting (admin only)
+func ResolveChatReport(c *gin.Context) {
+	adminUserID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var input struct {
+		Status    string `json:"status" binding:"required,oneof=resolved dismissed"`
+		Note      string `json:"note"`
+		BlockUser *bool  `json:"block_user"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	var report models.ChatReport
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&report).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Report not found"))
+		return
+	}
+
+	adminID := adminUserID.(uuid.UUID)
+	auditBefore := map[string]interface{}{"status": report.Status, "resolution_note": report.ResolutionNote}
+	tx := config.DB.Begin()
+
+	if err := tx.Model(&report).Updates(map[string]interface{}{
+		"status":          input.Status,
+		"resolution_note": input.Note,
+		"resolved_by":     adminID,
+		"updated_at":      time.Now().In(jakartaLoc),
+	}).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to update report"))
+		return
+	}
+
+	if input.BlockUser != nil {
+		if err := tx.Model(&models.User{}).Where("id = ?", report.ReportedUserID).Update("chat_blocked", *input.BlockUser).Error; err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.Internal("Failed to update user"))
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update report").Wrap(err))
+		return
+	}
+
+	auditAfter := map[string]interface{}{"status": input.Status, "resolution_note": input.Note}
+	if input.BlockUser != nil {
+		auditAfter["block_user"] = *input.BlockUser
+	}
+	recordAudit(c, "chat_report.resolve", "chat_report", report.ID.String(), auditBefore, auditAfter)
+
+	config.DB.Preload("Reporter").Preload("ReportedUser").Preload("Message").First(&report, "id = ?", report.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Report updated",
+		"report":  report,
+	})
+}
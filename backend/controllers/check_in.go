@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"backend-api/utils"
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+// Side length in pixels of the check-in QR code PNG
+const checkInQRSize = 512
+
+// checkInMaxDistance is how far in meters the picker may scan from the pickup location before the
+// check-in is flagged, from CHECK_IN_MAX_DISTANCE_M (default 500)
+func checkInMaxDistance() float64 {
+	meters, err := strconv.ParseFloat(os.Getenv("CHECK_IN_MAX_DISTANCE_M"), 64)
+	if err != nil || meters <= 0 {
+		return 500
+	}
+	return meters
+}
+
+// checkInCode is the content of a deposit's QR code: "<deposit id>.<token>"
+func checkInCode(deposit models.WasteDeposit) string {
+	return deposit.ID.String() + "." + deposit.CheckInToken
+}
+
+// canCheckIn reports whether a deposit is still waiting for the picker to arrive
+func canCheckIn(deposit models.WasteDeposit) bool {
+	return deposit.CheckedInAt == nil && (deposit.Status == "pending" || deposit.Status == "proses")
+}
+
+// GetDepositQRCode returns the check-in QR code PNG the school shows to the picker
+// (deposit owner or a coordinator of its school, admins included only as such). The token is created on first request.
+func GetDepositQRCode(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+	currentUserID := userID.(uuid.UUID)
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+	// Respond 404 rather than 403 so deposit IDs can't be probed
+	// Pickers are admins, so there is no admin shortcut: a picker holding the code would not need to visit
+	if deposit.UserID != currentUserID && (deposit.SchoolID == nil || !isSchoolCoordinator(currentUserID, *deposit.SchoolID)) {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+	if !canCheckIn(deposit) {
+		apperr.Abort(c, apperr.Conflict("This deposit is no longer waiting for pickup"))
+		return
+	}
+
+	if deposit.CheckInToken == "" {
+		token, err := utils.RandomToken()
+		if err != nil {
+			apperr.Abort(c, apperr.Internal("Failed to generate QR code").Wrap(err))
+			return
+		}
+		// Two devices opening the QR at once must end up with the same token
+		config.DB.Model(&models.WasteDeposit{}).
+			Where("id = ? AND (check_in_token = '' OR check_in_token IS NULL)", deposit.ID).
+			Update("check_in_token", token)
+		if err := config.DB.Select("id", "check_in_token").Where("id = ?", deposit.ID).First(&deposit).Error; err != nil || deposit.CheckInToken == "" {
+			apperr.Abort(c, apperr.Internal("Failed to generate QR code"))
+			return
+		}
+	}
+
+	// Whoever saw the code can't also be the one scanning it
+	if !containsString(deposit.CheckInFetchedBy, currentUserID.String()) {
+		fetchedBy := append(deposit.CheckInFetchedBy, currentUserID.String())
+		if err := config.DB.Model(&models.WasteDeposit{}).Where("id = ?", deposit.ID).Update("check_in_fetched_by", fetchedBy).Error; err != nil {
+			apperr.Abort(c, apperr.Internal("Failed to generate QR code").Wrap(err))
+			return
+		}
+	}
+
+	png, err := qrcode.Encode(checkInCode(deposit), qrcode.Medium, checkInQRSize)
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to generate QR code").Wrap(err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// CheckInDeposit records the picker's visit from the scanned QR code (admin only).
+// A pending deposit moves to proses with the scanner as picker. Each code works once.
+func CheckInDeposit(c *gin.Context) {
+	adminUserID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+	adminID := adminUserID.(uuid.UUID)
+
+	var input struct {
+		Code      string   `json:"code" binding:"required"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	fields := apperr.Fields{}
+	if (input.Latitude == nil) != (input.Longitude == nil) {
+		fields["latitude"] = "latitude and longitude must be sent together"
+	}
+	if input.Latitude != nil && (*input.Latitude < -90 || *input.Latitude > 90) {
+		fields["latitude"] = "must be between -90 and 90"
+	}
+	if input.Longitude != nil && (*input.Longitude < -180 || *input.Longitude > 180) {
+		fields["longitude"] = "must be between -180 and 180"
+	}
+	if len(fields) > 0 {
+		apperr.Abort(c, apperr.Validation(fields))
+		return
+	}
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	depositPart, token, _ := strings.Cut(strings.TrimSpace(input.Code), ".")
+	if depositPart != deposit.ID.String() {
+		apperr.Abort(c, apperr.BadRequest("This QR code belongs to a different deposit").WithCode(apperr.CodeCheckInInvalid))
+		return
+	}
+	if deposit.CheckInToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(deposit.CheckInToken)) != 1 {
+		apperr.Abort(c, apperr.BadRequest("Invalid QR code").WithCode(apperr.CodeCheckInInvalid))
+		return
+	}
+	if deposit.CheckedInAt != nil {
+		apperr.Abort(c, apperr.Conflict("This QR code has already been used").WithCode(apperr.CodeCheckInUsed))
+		return
+	}
+	if containsString(deposit.CheckInFetchedBy, adminID.String()) {
+		apperr.Abort(c, apperr.Forbidden("The picker can't check in with a QR code they opened themselves").WithCode(apperr.CodeCheckInInvalid))
+		return
+	}
+	if !canCheckIn(deposit) {
+		apperr.Abort(c, apperr.Conflict("This deposit is no longer waiting for pickup"))
+		return
+	}
+	if deposit.Status == "proses" && deposit.PickerID != nil && *deposit.PickerID != adminID {
+		apperr.Abort(c, apperr.Forbidden("This deposit is assigned to another picker"))
+		return
+	}
+
+	auditBefore := depositAuditFields(deposit)
+	oldStatus := deposit.Status
+	now := time.Now().In(jakartaLoc)
+	updates := map[string]interface{}{
+		"checked_in_at":      now,
+		"checked_in_by":      adminID,
+		"check_in_latitude":  input.Latitude,
+		"check_in_longitude": input.Longitude,
+		"updated_at":         now,
+	}
+	// Flag check-ins that don't place the picker at the pickup location, for an admin to look at
+	if deposit.Latitude != nil && deposit.Longitude != nil {
+		if input.Latitude != nil {
+			distance := utils.DistanceMeters(*input.Latitude, *input.Longitude, *deposit.Latitude, *deposit.Longitude)
+			updates["check_in_distance"] = distance
+			updates["check_in_flagged"] = distance > checkInMaxDistance()
+		} else {
+			updates["check_in_flagged"] = true
+		}
+	}
+	if oldStatus == "pending" {
+		updates["status"] = "proses"
+		updates["processed_at"] = now
+		updates["picker_id"] = adminID
+		var adminUser models.User
+		if err := config.DB.Where("id = ?", adminID).First(&adminUser).Error; err == nil {
+			updates["picker_name"] = adminUser.Name
+		}
+	}
+
+	// The token condition makes a second scan of the same code fail even when both arrive at once
+	result := config.DB.Model(&models.WasteDeposit{}).
+		Where("id = ? AND check_in_token = ? AND checked_in_at IS NULL AND status = ?", deposit.ID, deposit.CheckInToken, oldStatus).
+		Updates(updates)
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to check in deposit").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperr.Abort(c, apperr.Conflict("This QR code has already been used").WithCode(apperr.CodeCheckInUsed))
+		return
+	}
+
+	if err := config.DB.Where("id = ?", deposit.ID).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to check in deposit").Wrap(err))
+		return
+	}
+
+	auditAfter := depositAuditFields(deposit)
+	auditBefore["checked_in_at"], auditAfter["checked_in_at"] = nil, now
+	if deposit.CheckInDistance != nil {
+		auditAfter["check_in_distance_m"] = *deposit.CheckInDistance
+	}
+	if deposit.CheckInFlagged {
+		auditAfter["check_in_flagged"] = true
+	}
+	recordAudit(c, "deposit.check_in", "deposit", deposit.ID.String(), auditBefore, auditAfter)
+
+	if oldStatus == "pending" {
+		CreateNotification(deposit.UserID, &deposit.ID, "deposit.processing", depositNotificationParams(deposit), "deposit_status")
+	}
+
+	response := gin.H{
+		"message": "Deposit checked in successfully",
+		"deposit": withSignedPhoto(c.Request.Context(), deposit),
+	}
+	// How far the picker was from the pickup location, when both are known
+	if deposit.CheckInDistance != nil {
+		response["distance_m"] = *deposit.CheckInDistance
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/utils"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date layouts accepted for pickup_date
+var pickupDateLayouts = []string{"02/01/2006", "2006-01-02"}
+
+// depositMaxBins is the largest bin count accepted per deposit, from DEPOSIT_MAX_BINS (default 50)
+func depositMaxBins() int {
+	bins, err := strconv.Atoi(os.Getenv("DEPOSIT_MAX_BINS"))
+	if err != nil || bins < 1 {
+		return 50
+	}
+	return bins
+}
+
+// pickupHorizonDays is how many days ahead a pickup may be requested, from PICKUP_HORIZON_DAYS (default 60)
+func pickupHorizonDays() int {
+	days, err := strconv.Atoi(os.Getenv("PICKUP_HORIZON_DAYS"))
+	if err != nil || days < 1 {
+		return 60
+	}
+	return days
+}
+
+// validateBinCount parses bin_count and checks it against depositMaxBins.
+// Returns the field error message and its params, an empty message when the count is valid.
+func validateBinCount(n json.Number) (int, string, apperr.Params) {
+	binCount, err := strconv.Atoi(n.String())
+	switch {
+	case err != nil:
+		return binCount, "must be a whole number", nil
+	case binCount < 1 || binCount > depositMaxBins():
+		return binCount, "must be between {min} and {max}", apperr.Params{"min": "1", "max": strconv.Itoa(depositMaxBins())}
+	}
+	return binCount, "", nil
+}
+
+// validatedDeposit holds the parsed values of a WasteDepositInput that passed validation
+type validatedDeposit struct {
+	PickupDate   time.Time
+	BinCount     int
+	ContactPhone string // E.164, empty when not given
+}
+
+// validateDepositInput checks the rules binding tags cannot express.
+// Contact fields may be empty here because a saved pickup address can fill them later.
+func validateDepositInput(input *WasteDepositInput) (validatedDeposit, apperr.Fields, apperr.FieldParams) {
+	var result validatedDeposit
+	fields := apperr.Fields{}
+	params := apperr.FieldParams{}
+
+	input.ContactName = strings.TrimSpace(input.ContactName)
+	input.Address = strings.TrimSpace(input.Address)
+	input.WasteType = strings.TrimSpace(input.WasteType)
+
+	binCount, msg, binParams := validateBinCount(input.BinCount)
+	if msg != "" {
+		fields["bin_count"], params["bin_count"] = msg, binParams
+	}
+	result.BinCount = binCount
+
+	var pickupDate time.Time
+	var err error
+	for _, layout := range pickupDateLayouts {
+		if pickupDate, err = time.Parse(layout, strings.TrimSpace(input.PickupDate)); err == nil {
+			break
+		}
+	}
+	today := pickupDay(time.Now())
+	switch {
+	case err != nil:
+		fields["pickup_date"] = "must be a date in DD/MM/YYYY format"
+	case pickupDate.Before(today):
+		fields["pickup_date"] = "must not be in the past"
+	case pickupDate.After(today.AddDate(0, 0, pickupHorizonDays())):
+		fields["pickup_date"] = "must be within {days} days"
+		params["pickup_date"] = apperr.Params{"days": strconv.Itoa(pickupHorizonDays())}
+	}
+	result.PickupDate = pickupDate
+
+	if input.ContactPhone != "" {
+		phone, err := utils.NormalizePhone(input.ContactPhone)
+		if err != nil {
+			fields["contact_phone"] = "must be an Indonesian phone number, e.g. 081234567890"
+		}
+		result.ContactPhone = phone
+	}
+
+	if input.WasteType == "" {
+		fields["waste_type"] = "is required"
+	}
+
+	return result, fields, params
+}
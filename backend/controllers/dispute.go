@@ -0,0 +1,363 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Most photos accepted with a single dispute
+const maxDisputePhotos = 5
+
+var disputeStatuses = []string{"open", "correction_pending", "resolved", "rejected"}
+
+type DisputeInput struct {
+	Reason        string      `json:"reason" form:"reason" binding:"required,max=1000"`
+	ClaimedWeight json.Number `json:"claimed_weight" form:"claimed_weight"`
+}
+
+// withSignedDispute returns a copy of the dispute with signed photo URLs for the response
+func withSignedDispute(ctx context.Context, dispute models.DepositDispute) models.DepositDispute {
+	if len(dispute.Photos) > 0 {
+		photos := make([]string, len(dispute.Photos))
+		for i, p := range dispute.Photos {
+			photos[i] = signURL(ctx, p)
+		}
+		dispute.Photos = photos
+	}
+	if dispute.Deposit != nil {
+		deposit := withSignedPhoto(ctx, *dispute.Deposit)
+		dispute.Deposit = &deposit
+	}
+	return dispute
+}
+
+func withSignedDisputes(ctx context.Context, disputes []models.DepositDispute) []models.DepositDispute {
+	signed := make([]models.DepositDispute, len(disputes))
+	for i, d := range disputes {
+		signed[i] = withSignedDispute(ctx, d)
+	}
+	return signed
+}
+
+// latestDispute returns the most recent dispute of a deposit, or nil when it has none
+func latestDispute(depositID uuid.UUID) (*models.DepositDispute, error) {
+	var dispute models.DepositDispute
+	err := config.DB.Preload("WeightCorrection").Where("deposit_id = ?", depositID).Order("created_at DESC").First(&dispute).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// CreateDispute lets the deposit owner or a coordinator of its school dispute the weight of a completed deposit.
+// Accepts JSON or multipart with up to maxDisputePhotos files in "photos".
+func CreateDispute(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+	currentUserID := userID.(uuid.UUID)
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+	// Respond 404 rather than 403 so deposit IDs can't be probed
+	if deposit.UserID != currentUserID && (deposit.SchoolID == nil || !canManageSchool(currentUserID, *deposit.SchoolID)) {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+	if deposit.Status != "completed" || deposit.Weight == nil {
+		apperr.Abort(c, apperr.BadRequest("Only completed deposits with a confirmed weight can be disputed"))
+		return
+	}
+
+	var input DisputeInput
+	if err := c.ShouldBind(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		apperr.Abort(c, apperr.Validation(apperr.Fields{"reason": "is required"}))
+		return
+	}
+
+	var claimedWeight *float64
+	if input.ClaimedWeight != "" {
+		weight, err := input.ClaimedWeight.Float64()
+		if err != nil || weight <= 0 {
+			apperr.Abort(c, apperr.Validation(apperr.Fields{"claimed_weight": "must be greater than 0"}))
+			return
+		}
+		claimedWeight = &weight
+	}
+
+	var open int64
+	config.DB.Model(&models.DepositDispute{}).Where("deposit_id = ? AND status IN ?", deposit.ID, []string{"open", "correction_pending"}).Count(&open)
+	if open > 0 {
+		apperr.Abort(c, apperr.Conflict("This deposit already has an open dispute"))
+		return
+	}
+
+	var photos []string
+	if form, err := c.MultipartForm(); err == nil {
+		files := form.File["photos"]
+		if len(files) > maxDisputePhotos {
+			apperr.Abort(c, apperr.Validation(apperr.Fields{"photos": "must be at most {max} files"}).
+				WithFieldParams(apperr.FieldParams{"photos": {"max": strconv.Itoa(maxDisputePhotos)}}))
+			return
+		}
+		stamp := time.Now().Format("20060102150405")
+		for i, file := range files {
+			photoURL, _, err := saveUploadedImage(c, file, "disputes", deposit.ID.String()+"_"+stamp+"_"+strconv.Itoa(i), false)
+			if err != nil {
+				for _, saved := range photos {
+					deleteStoredFiles(saved, nil)
+				}
+				respondUploadError(c, err, "Failed to save file")
+				return
+			}
+			photos = append(photos, photoURL)
+		}
+	}
+
+	dispute := models.DepositDispute{
+		DepositID:     deposit.ID,
+		RaisedBy:      currentUserID,
+		Reason:        reason,
+		ClaimedWeight: claimedWeight,
+		Photos:        photos,
+		Status:        "open",
+	}
+	if err := config.DB.Create(&dispute).Error; err != nil {
+		for _, saved := range photos {
+			deleteStoredFiles(saved, nil)
+		}
+		apperr.Abort(c, apperr.Internal("Failed to create dispute").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Dispute submitted successfully",
+		"dispute": withSignedDispute(c.Request.Context(), dispute),
+	})
+}
+
+// GetDisputes is the admin review queue, oldest open dispute first, filtered by ?status= with ?page= and ?limit= (admin only)
+func GetDisputes(c *gin.Context) {
+	page, limit, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.DepositDispute{})
+	if status := c.Query("status"); status != "" {
+		if !containsString(disputeStatuses, status) {
+			apperr.Abort(c, apperr.BadRequest("Invalid status. Must be: open, correction_pending, resolved, or rejected"))
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch disputes").Wrap(err))
+		return
+	}
+
+	var disputes []models.DepositDispute
+	if err := query.Preload("Deposit").Preload("Raiser").Preload("Resolver").Preload("WeightCorrection").
+		Order("status = 'open' DESC, created_at").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&disputes).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch disputes").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"disputes": withSignedDisputes(c.Request.Context(), disputes),
+		"page":     page,
+		"limit":    limit,
+		"total":    total,
+	})
+}
+
+// GetDisputeByID returns a dispute with its deposit and linked weight correction (admin only)
+func GetDisputeByID(c *gin.Context) {
+	var dispute models.DepositDispute
+	if err := config.DB.Preload("Deposit").Preload("Raiser").Preload("Resolver").Preload("WeightCorrection").
+		Where("id = ?", c.Param("id")).First(&dispute).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Dispute not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dispute": withSignedDispute(c.Request.Context(), dispute)})
+}
+
+// ResolveDispute accepts a dispute (admin only). When a weight is given a weight correction is opened
+// and linked to the dispute, which stays correction_pending until a second admin reviews the correction.
+func ResolveDispute(c *gin.Context) {
+	reviewDispute(c, true)
+}
+
+// RejectDispute closes a dispute without changing the deposit (admin only)
+func RejectDispute(c *gin.Context) {
+	reviewDispute(c, false)
+}
+
+func reviewDispute(c *gin.Context, resolve bool) {
+	adminUserID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+	adminID := adminUserID.(uuid.UUID)
+
+	var input struct {
+		Note   string   `json:"note" binding:"required,max=1000"`
+		Weight *float64 `json:"weight"` // Only used when resolving
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	if input.Weight != nil && *input.Weight <= 0 {
+		apperr.Abort(c, apperr.Validation(apperr.Fields{"weight": "must be greater than 0"}))
+		return
+	}
+
+	var dispute models.DepositDispute
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&dispute).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Dispute not found"))
+		return
+	}
+	if dispute.Status != "open" {
+		apperr.Abort(c, apperr.Conflict("Dispute has already been reviewed"))
+		return
+	}
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", dispute.DepositID).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	status, action, templateKey := "rejected", "dispute.reject", "dispute.rejected"
+	if resolve {
+		status, action, templateKey = "resolved", "dispute.resolve", "dispute.resolved"
+	}
+	note := strings.TrimSpace(input.Note)
+	now := time.Now().In(jakartaLoc)
+
+	tx := config.DB.Begin()
+	updates := map[string]interface{}{
+		"status":          status,
+		"resolved_by":     adminID,
+		"resolution_note": note,
+		"resolved_at":     now,
+		"updated_at":      now,
+	}
+	if resolve && input.Weight != nil {
+		correction, appErr := requestWeightCorrection(tx, deposit, *input.Weight, "Dispute: "+dispute.Reason, adminID)
+		if appErr != nil {
+			tx.Rollback()
+			apperr.Abort(c, appErr)
+			return
+		}
+		// The outcome is only final, and the school only told, once the correction is approved or rejected
+		status = "correction_pending"
+		updates["status"] = status
+		updates["weight_correction_id"] = correction.ID
+		delete(updates, "resolved_at")
+	}
+
+	// Only one reviewer can move the dispute out of open
+	result := tx.Model(&models.DepositDispute{}).Where("id = ? AND status = ?", dispute.ID, "open").Updates(updates)
+	if result.Error != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to review dispute").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Conflict("Dispute has already been reviewed"))
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to review dispute").Wrap(err))
+		return
+	}
+
+	auditAfter := map[string]interface{}{"status": status, "resolution_note": note}
+	if id, ok := updates["weight_correction_id"]; ok {
+		auditAfter["weight_correction_id"] = id.(uuid.UUID).String()
+	}
+	recordAudit(c, action, "dispute", dispute.ID.String(), map[string]interface{}{"status": "open"}, auditAfter)
+
+	if status != "correction_pending" {
+		notifyDisputeReviewed(dispute.RaisedBy, deposit, templateKey, note)
+	}
+
+	config.DB.Preload("Deposit").Preload("Raiser").Preload("Resolver").Preload("WeightCorrection").First(&dispute, "id = ?", dispute.ID)
+
+	message := "Dispute " + status + " successfully"
+	if status == "correction_pending" {
+		message = "Dispute accepted, the weight correction is waiting for approval"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"dispute": withSignedDispute(c.Request.Context(), dispute),
+	})
+}
+
+// notifyDisputeReviewed tells whoever raised the dispute about its final outcome
+func notifyDisputeReviewed(raisedBy uuid.UUID, deposit models.WasteDeposit, templateKey, note string) {
+	params := depositNotificationParams(deposit)
+	params["note"] = note
+	CreateNotification(raisedBy, &deposit.ID, templateKey, params, "deposit_status")
+}
+
+// settleDisputeForCorrection gives a correction_pending dispute the outcome of its weight correction:
+// resolved when the correction is approved, rejected otherwise. Returns nil when no dispute waits on it.
+func settleDisputeForCorrection(tx *gorm.DB, correctionID uuid.UUID, approved bool, now time.Time) (*models.DepositDispute, error) {
+	var disputes []models.DepositDispute
+	if err := tx.Where("weight_correction_id = ? AND status = ?", correctionID, "correction_pending").Limit(1).Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+	if len(disputes) == 0 {
+		return nil, nil
+	}
+	dispute := disputes[0]
+	dispute.Status = "rejected"
+	if approved {
+		dispute.Status = "resolved"
+	}
+	dispute.ResolvedAt = &now
+	if err := tx.Model(&models.DepositDispute{}).Where("id = ?", dispute.ID).Updates(map[string]interface{}{
+		"status":      dispute.Status,
+		"resolved_at": now,
+		"updated_at":  now,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
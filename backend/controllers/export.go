@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// depositExportRow is one exported deposit with the owner and picker flattened in
+type depositExportRow struct {
+	ID           string
+	CreatedAt    time.Time
+	UserName     string
+	UserEmail    string
+	SchoolName   string
+	ContactName  string
+	ContactPhone string
+	Address      string
+	PickupDate   time.Time
+	WasteType    string
+	BinCount     int
+	Weight       *float64
+	Status       string
+	PickerName   string
+	ProcessedAt  *time.Time
+	CompletedAt  *time.Time
+	RejectedAt   *time.Time
+}
+
+var depositExportHeader = []string{
+	"ID", "Dibuat", "Nama User", "Email User", "Sekolah", "Nama Kontak", "Telepon Kontak", "Alamat",
+	"Tanggal Penjemputan", "Jenis Sampah", "Jumlah Tong", "Berat (Kg)", "Status", "Penjemput",
+	"Diproses", "Selesai", "Ditolak",
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.In(jakartaLoc).Format("2006-01-02 15:04")
+}
+
+// escapeExportCell stops spreadsheet apps from running user-entered CSV text as a formula
+// by prefixing values that start with a formula character with a quote. XLSX cells are
+// written as strings and never evaluated, so they keep the raw value.
+func escapeExportCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (r depositExportRow) values() []string {
+	weight := ""
+	if r.Weight != nil {
+		weight = strconv.FormatFloat(*r.Weight, 'f', -1, 64)
+	}
+	return []string{
+		r.ID,
+		formatExportTime(&r.CreatedAt),
+		r.UserName,
+		r.UserEmail,
+		r.SchoolName,
+		r.ContactName,
+		r.ContactPhone,
+		r.Address,
+		r.PickupDate.In(jakartaLoc).Format("2006-01-02"),
+		r.WasteType,
+		strconv.Itoa(r.BinCount),
+		weight,
+		r.Status,
+		r.PickerName,
+		formatExportTime(r.ProcessedAt),
+		formatExportTime(r.CompletedAt),
+		formatExportTime(r.RejectedAt),
+	}
+}
+
+// ExportDeposits streams deposits as ?format=csv (default) or xlsx, using the same filters as GetAllDeposits (admin only).
+// Text entered by users is escaped in both formats so it can't run as a formula.
+func ExportDeposits(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		apperr.Abort(c, apperr.BadRequest("Invalid format. Must be: csv or xlsx"))
+		return
+	}
+
+	query, ok := applyDepositFilters(c, config.DB.Model(&models.WasteDeposit{}))
+	if !ok {
+		return
+	}
+
+	rows, err := query.
+		Select("waste_deposits.id, waste_deposits.created_at, users.name AS user_name, users.email AS user_email, " +
+			"waste_deposits.school_name, waste_deposits.contact_name, waste_deposits.contact_phone, waste_deposits.address, " +
+			"waste_deposits.pickup_date, waste_deposits.waste_type, waste_deposits.bin_count, waste_deposits.weight, " +
+			"waste_deposits.status, waste_deposits.picker_name, waste_deposits.processed_at, " +
+			"waste_deposits.completed_at, waste_deposits.rejected_at").
+		Joins("LEFT JOIN users ON users.id = waste_deposits.user_id").
+		Order("waste_deposits.created_at DESC").
+		Rows()
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to export deposits").Wrap(err))
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("penyetoran_%s.%s", time.Now().In(jakartaLoc).Format("20060102_150405"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	// Rows are read from the cursor one at a time so large exports never sit in memory
+	next := func() ([]string, error) {
+		if !rows.Next() {
+			return nil, rows.Err()
+		}
+		var row depositExportRow
+		if err := config.DB.ScanRows(rows, &row); err != nil {
+			return nil, err
+		}
+		return row.values(), nil
+	}
+
+	if format == "csv" {
+		exportCSV(c, next)
+	} else {
+		exportXLSX(c, next)
+	}
+}
+
+func exportCSV(c *gin.Context, next func() ([]string, error)) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	// BOM so Excel opens the file as UTF-8
+	c.Writer.Write([]byte("\xEF\xBB\xBF"))
+	w := csv.NewWriter(c.Writer)
+	w.Write(depositExportHeader)
+
+	count := 0
+	for {
+		values, err := next()
+		if err != nil {
+			// Headers are already sent, all we can do is stop and log
+			log.Println("Deposit export:", err)
+			break
+		}
+		if values == nil {
+			break
+		}
+		for i, v := range values {
+			values[i] = escapeExportCell(v)
+		}
+		w.Write(values)
+
+		count++
+		if count%500 == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+	}
+	w.Flush()
+}
+
+func exportXLSX(c *gin.Context, next func() ([]string, error)) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Penyetoran"
+	f.SetSheetName("Sheet1", sheet)
+
+	// The stream writer spills rows to a temporary file instead of keeping them in memory
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to export deposits").Wrap(err))
+		return
+	}
+
+	toCells := func(values []string) []interface{} {
+		cells := make([]interface{}, len(values))
+		for i, v := range values {
+			cells[i] = v
+		}
+		return cells
+	}
+
+	if err := sw.SetRow("A1", toCells(depositExportHeader)); err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to export deposits").Wrap(err))
+		return
+	}
+
+	rowNum := 2
+	for {
+		values, err := next()
+		if err != nil {
+			apperr.Abort(c, apperr.Internal("Failed to export deposits").Wrap(err))
+			return
+		}
+		if values == nil {
+			break
+		}
+
+		cells := toCells(values)
+		// Keep numeric columns numeric so they can be summed in Excel
+		if n, err := strconv.Atoi(values[10]); err == nil {
+			cells[10] = n
+		}
+		if w, err := strconv.ParseFloat(values[11], 64); err == nil {
+			cells[11] = w
+		}
+
+		cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+		if err := sw.SetRow(cell, cells); err != nil {
+			apperr.Abort(c, apperr.Internal("Failed to export deposits").Wrap(err))
+			return
+		}
+		rowNum++
+	}
+
+	if err := sw.Flush(); err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to export deposits").Wrap(err))
+		return
+	}
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Status(http.StatusOK)
+	if err := f.Write(c.Writer); err != nil {
+		log.Println("Deposit export:", err)
+	}
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyImpact returns the environmental impact of the completed deposits of the authenticated user's school,
+// from every member: weight by waste type, pickups, points, a monthly trend and estimated CO2-equivalent avoided.
+func GetMyImpact(c *gin.Context) {
+	user, ok := currentSchoolUser(c)
+	if !ok {
+		return
+	}
+
+	schoolID := *user.SchoolID
+	factors := config.EmissionFactors()
+	factorFor := func(wasteType string) float64 {
+		if f, ok := factors[wasteType]; ok {
+			return f
+		}
+		return config.DefaultEmissionFactor
+	}
+
+	// One row per month and waste type, folded into totals below
+	var rows []struct {
+		Month     string
+		WasteType string
+		Weight    float64
+		Pickups   int64
+	}
+	if err := config.DB.Model(&models.WasteDeposit{}).
+		Select("to_char(pickup_date AT TIME ZONE 'Asia/Jakarta', 'YYYY-MM') AS month, waste_type, "+
+			"COALESCE(SUM(weight), 0) AS weight, COUNT(*) AS pickups").
+		Where("school_id = ? AND status = ?", schoolID, "completed").
+		Group("month, waste_type").
+		Order("month").
+		Scan(&rows).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to compute impact").Wrap(err))
+		return
+	}
+
+	type wasteTypeImpact struct {
+		WasteType string  `json:"waste_type"`
+		Weight    float64 `json:"weight"`
+		Pickups   int64   `json:"pickups"`
+		CO2e      float64 `json:"co2e_avoided"`
+	}
+	type monthImpact struct {
+		Month   string  `json:"month"`
+		Weight  float64 `json:"weight"`
+		Pickups int64   `json:"pickups"`
+		CO2e    float64 `json:"co2e_avoided"`
+	}
+
+	var totalWeight, totalCO2e float64
+	var totalPickups int64
+	byType := map[string]*wasteTypeImpact{}
+	monthly := []monthImpact{}
+	for _, r := range rows {
+		co2e := r.Weight * factorFor(r.WasteType)
+		totalWeight += r.Weight
+		totalPickups += r.Pickups
+		totalCO2e += co2e
+
+		t, ok := byType[r.WasteType]
+		if !ok {
+			t = &wasteTypeImpact{WasteType: r.WasteType}
+			byType[r.WasteType] = t
+		}
+		t.Weight += r.Weight
+		t.Pickups += r.Pickups
+		t.CO2e += co2e
+
+		if n := len(monthly); n == 0 || monthly[n-1].Month != r.Month {
+			monthly = append(monthly, monthImpact{Month: r.Month})
+		}
+		m := &monthly[len(monthly)-1]
+		m.Weight += r.Weight
+		m.Pickups += r.Pickups
+		m.CO2e += co2e
+	}
+
+	weightByType := make([]wasteTypeImpact, 0, len(byType))
+	for _, t := range byType {
+		weightByType = append(weightByType, *t)
+	}
+	sort.Slice(weightByType, func(i, j int) bool { return weightByType[i].Weight > weightByType[j].Weight })
+
+	c.JSON(http.StatusOK, gin.H{
+		"school_id":            schoolID,
+		"school_name":          user.SchoolName,
+		"total_weight":         totalWeight,
+		"pickups":              totalPickups,
+		"points":               schoolPoints(schoolID),
+		"my_points":            userPoints(user.ID),
+		"co2e_avoided":         totalCO2e,
+		"weight_by_waste_type": weightByType,
+		"monthly":              monthly,
+		"emission_factors":     factors,
+	})
+}
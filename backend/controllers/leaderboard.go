@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Cached rankings are also dropped after this long so period boundaries roll over
+const leaderboardTTL = 10 * time.Minute
+
+var (
+	leaderboardPeriods = []string{"week", "month", "year", "all"}
+	leaderboardMetrics = []string{"weight", "points"}
+)
+
+type LeaderboardEntry struct {
+	Rank       int       `json:"rank"`
+	SchoolID   uuid.UUID `json:"school_id"`
+	SchoolName string    `json:"school_name"`
+	Value      float64   `json:"value"`
+}
+
+type leaderboard struct {
+	Entries    []LeaderboardEntry
+	ComputedAt time.Time
+}
+
+var leaderboardCache = struct {
+	sync.RWMutex
+	boards map[string]*leaderboard
+}{boards: map[string]*leaderboard{}}
+
+// periodStart returns the first moment of the current period in Jakarta time, zero for all-time
+func periodStart(period string, now time.Time) time.Time {
+	now = now.In(jakartaLoc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jakartaLoc)
+	switch period {
+	case "week":
+		// Weeks start on Monday
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, jakartaLoc)
+	case "year":
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, jakartaLoc)
+	}
+	return time.Time{}
+}
+
+// computeLeaderboard ranks schools by completed weight or points earned in the period
+func computeLeaderboard(period, metric string) (*leaderboard, error) {
+	now := time.Now()
+	start := periodStart(period, now)
+
+	var rows []struct {
+		SchoolID   uuid.UUID
+		SchoolName string
+		Value      float64
+	}
+
+	var err error
+	if metric == "points" {
+		err = config.DB.Model(&models.PointTransaction{}).
+			Select("schools.id AS school_id, schools.name AS school_name, SUM(point_transactions.points) AS value").
+			Joins("JOIN users ON users.id = point_transactions.user_id").
+			Joins("JOIN schools ON schools.id = users.school_id").
+			Where("point_transactions.created_at >= ?", start).
+			Group("schools.id, schools.name").
+			Having("SUM(point_transactions.points) > 0").
+			Order("value DESC, school_name").
+			Scan(&rows).Error
+	} else {
+		err = config.DB.Model(&models.WasteDeposit{}).
+			Select("schools.id AS school_id, schools.name AS school_name, SUM(waste_deposits.weight) AS value").
+			Joins("JOIN schools ON schools.id = waste_deposits.school_id").
+			Where("waste_deposits.status = ? AND waste_deposits.weight IS NOT NULL AND waste_deposits.pickup_date >= ?", "completed", start).
+			Group("schools.id, schools.name").
+			Order("value DESC, school_name").
+			Scan(&rows).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]LeaderboardEntry, len(rows))
+	for i, r := range rows {
+		rank := i + 1
+		// Schools with the same value share a rank (1, 2, 2, 4)
+		if i > 0 && r.Value == rows[i-1].Value {
+			rank = entries[i-1].Rank
+		}
+		entries[i] = LeaderboardEntry{Rank: rank, SchoolID: r.SchoolID, SchoolName: r.SchoolName, Value: r.Value}
+	}
+
+	return &leaderboard{Entries: entries, ComputedAt: now}, nil
+}
+
+func getLeaderboard(period, metric string) (*leaderboard, error) {
+	key := period + "/" + metric
+
+	leaderboardCache.RLock()
+	board, ok := leaderboardCache.boards[key]
+	leaderboardCache.RUnlock()
+	if ok && time.Since(board.ComputedAt) < leaderboardTTL {
+		return board, nil
+	}
+
+	board, err := computeLeaderboard(period, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboardCache.Lock()
+	leaderboardCache.boards[key] = board
+	leaderboardCache.Unlock()
+	return board, nil
+}
+
+// refreshLeaderboards recomputes every cached ranking, called when a deposit is completed or its weight changes
+func refreshLeaderboards() {
+	for _, period := range leaderboardPeriods {
+		for _, metric := range leaderboardMetrics {
+			board, err := computeLeaderboard(period, metric)
+			if err != nil {
+				log.Println("Failed to refresh leaderboard:", err)
+				return
+			}
+			leaderboardCache.Lock()
+			leaderboardCache.boards[period+"/"+metric] = board
+			leaderboardCache.Unlock()
+		}
+	}
+}
+
+// GetLeaderboard ranks schools by completed weight or points.
+// Supports ?period=week|month|year|all (default month), ?metric=weight|points (default weight) and ?limit=.
+func GetLeaderboard(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	period := c.DefaultQuery("period", "month")
+	if !containsString(leaderboardPeriods, period) {
+		apperr.Abort(c, apperr.BadRequest("Invalid period. Must be: week, month, year, or all"))
+		return
+	}
+	metric := c.DefaultQuery("metric", "weight")
+	if !containsString(leaderboardMetrics, metric) {
+		apperr.Abort(c, apperr.BadRequest("Invalid metric. Must be: weight or points"))
+		return
+	}
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			apperr.Abort(c, apperr.BadRequest("Invalid limit"))
+			return
+		}
+		limit = parsed
+	}
+
+	board, err := getLeaderboard(period, metric)
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to compute leaderboard").Wrap(err))
+		return
+	}
+
+	var user models.User
+	config.DB.Where("id = ?", userID.(uuid.UUID)).First(&user)
+
+	var mySchool *LeaderboardEntry
+	for i := range board.Entries {
+		if user.SchoolID != nil && board.Entries[i].SchoolID == *user.SchoolID {
+			mySchool = &board.Entries[i]
+			break
+		}
+	}
+
+	entries := board.Entries
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	var start *time.Time
+	if period != "all" {
+		s := periodStart(period, board.ComputedAt)
+		start = &s
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"period":       period,
+		"metric":       metric,
+		"period_start": start,
+		"entries":      entries,
+		"my_school":    mySchool,
+		"total":        len(board.Entries),
+		"computed_at":  board.ComputedAt.In(jakartaLoc),
+	})
+}
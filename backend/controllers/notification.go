@@ -1,30 +1,135 @@
 package controllers
 
 import (
+	"backend-api/apperr"
 	"backend-api/config"
+	"backend-api/i18n"
 	"backend-api/models"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
 )
 
-// GetMyNotifications returns all notifications for the authenticated user
+// GetMyNotifications returns a page of notifications for the authenticated user.
+// Supports ?page=, ?limit=, ?type= and ?is_read=true|false.
 func GetMyNotifications(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	page, limit, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.Notification{}).Where("user_id = ?", userID.(uuid.UUID))
+	if notifType := c.Query("type"); notifType != "" {
+		query = query.Where("type = ?", notifType)
+	}
+	if isReadStr := c.Query("is_read"); isReadStr != "" {
+		isRead, err := strconv.ParseBool(isReadStr)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid is_read"))
+			return
+		}
+		query = query.Where("is_read = ?", isRead)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch notifications").Wrap(err))
 		return
 	}
 
 	var notifications []models.Notification
-	if err := config.DB.Where("user_id = ?", userID.(uuid.UUID)).Order("created_at DESC").Find(&notifications).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+	if err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&notifications).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch notifications").Wrap(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"notifications": notifications,
+		"notifications": localizeNotifications(c.GetString("locale"), notifications),
+		"page":          page,
+		"limit":         limit,
+		"total":         total,
+	})
+}
+
+// DeleteNotification deletes a single notification of the authenticated user
+func DeleteNotification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	// A malformed id can't match any notification, so it is reported like a missing one
+	notifID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		apperr.Abort(c, apperr.NotFound("Notification not found"))
+		return
+	}
+
+	result := config.DB.Where("id = ? AND user_id = ?", notifID, userID.(uuid.UUID)).Delete(&models.Notification{})
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to delete notification").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperr.Abort(c, apperr.NotFound("Notification not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted"})
+}
+
+// DeleteNotifications deletes the given notification IDs, or every read notification when all_read is true
+func DeleteNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var input struct {
+		IDs     []uuid.UUID `json:"ids"`
+		AllRead bool        `json:"all_read"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	if len(input.IDs) == 0 && !input.AllRead {
+		apperr.Abort(c, apperr.BadRequest("Provide ids or all_read"))
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", userID.(uuid.UUID))
+	if len(input.IDs) > 0 {
+		query = query.Where("id IN ?", input.IDs)
+	}
+	if input.AllRead {
+		query = query.Where("is_read = ?", true)
+	}
+
+	result := query.Delete(&models.Notification{})
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to delete notifications"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notifications deleted",
+		"deleted": result.RowsAffected,
 	})
 }
 
@@ -33,19 +138,19 @@ func MarkNotificationAsRead(c *gin.Context) {
 	notifID := c.Param("id")
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var notification models.Notification
 	if err := config.DB.Where("id = ? AND user_id = ?", notifID, userID.(uuid.UUID)).First(&notification).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		apperr.Abort(c, apperr.NotFound("Notification not found"))
 		return
 	}
 
 	notification.IsRead = true
 	if err := config.DB.Save(&notification).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		apperr.Abort(c, apperr.Internal("Failed to update notification").Wrap(err))
 		return
 	}
 
@@ -56,12 +161,12 @@ func MarkNotificationAsRead(c *gin.Context) {
 func MarkAllNotificationsAsRead(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	if err := config.DB.Model(&models.Notification{}).Where("user_id = ?", userID.(uuid.UUID)).Update("is_read", true).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		apperr.Abort(c, apperr.Internal("Failed to update notifications").Wrap(err))
 		return
 	}
 
@@ -72,7 +177,7 @@ func MarkAllNotificationsAsRead(c *gin.Context) {
 func GetUnreadNotificationCount(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
@@ -82,15 +187,246 @@ func GetUnreadNotificationCount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"unread_count": count})
 }
 
-// Helper function to create notification
-func CreateNotification(userID uuid.UUID, depositID *uuid.UUID, title, message, notifType string) error {
+// Notification categories users can opt in or out of
+var notificationCategories = []string{"deposit_status", "weight_confirmed", "chat", "points", "announcement"}
+
+// Channels a notification can be delivered on
+var notificationChannels = []string{"in_app", "push", "email"}
+
+// NotificationSender delivers a notification on an external channel such as push or email
+type NotificationSender interface {
+	Send(user models.User, notification models.Notification) error
+}
+
+var notificationSenders = map[string]NotificationSender{}
+
+// RegisterNotificationSender enables delivery on a channel other than in_app
+func RegisterNotificationSender(channel string, sender NotificationSender) {
+	notificationSenders[channel] = sender
+}
+
+// defaultNotificationPreference is used when the user has not saved a preference.
+// Chat messages already show up in the chat list, so they are not stored in-app by default.
+func defaultNotificationPreference(category, channel string) bool {
+	if category == "chat" && channel == "in_app" {
+		return false
+	}
+	return true
+}
+
+// notificationEnabled reports whether the user wants the category delivered on the channel
+func notificationEnabled(userID uuid.UUID, category, channel string) bool {
+	var pref models.NotificationPreference
+	if err := config.DB.Where("user_id = ? AND category = ? AND channel = ?", userID, category, channel).First(&pref).Error; err != nil {
+		return defaultNotificationPreference(category, channel)
+	}
+	return pref.Enabled
+}
+
+// GetNotificationPreferences returns the user's preference for every category and channel
+func GetNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var saved []models.NotificationPreference
+	if err := config.DB.Where("user_id = ?", userID.(uuid.UUID)).Find(&saved).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch preferences").Wrap(err))
+		return
+	}
+
+	savedMap := make(map[string]bool)
+	for _, p := range saved {
+		savedMap[p.Category+"/"+p.Channel] = p.Enabled
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(notificationCategories)*len(notificationChannels))
+	for _, category := range notificationCategories {
+		for _, channel := range notificationChannels {
+			enabled, ok := savedMap[category+"/"+channel]
+			if !ok {
+				enabled = defaultNotificationPreference(category, channel)
+			}
+			preferences = append(preferences, models.NotificationPreference{
+				Category: category,
+				Channel:  channel,
+				Enabled:  enabled,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": preferences,
+		"categories":  notificationCategories,
+		"channels":    notificationChannels,
+	})
+}
+
+// UpdateNotificationPreferences saves one or more category/channel preferences for the user
+func UpdateNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var input struct {
+		Preferences []struct {
+			Category string `json:"category" binding:"required"`
+			Channel  string `json:"channel" binding:"required"`
+			Enabled  *bool  `json:"enabled" binding:"required"`
+		} `json:"preferences" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	currentUserID := userID.(uuid.UUID)
+	preferences := make([]models.NotificationPreference, 0, len(input.Preferences))
+	// A pair sent twice keeps its last value; Postgres rejects an upsert that touches the same row twice
+	seen := map[[2]string]int{}
+	for _, p := range input.Preferences {
+		if !containsString(notificationCategories, p.Category) {
+			apperr.Abort(c, apperr.BadRequest("Invalid category: {category}").WithParams(apperr.Params{"category": p.Category}))
+			return
+		}
+		if !containsString(notificationChannels, p.Channel) {
+			apperr.Abort(c, apperr.BadRequest("Invalid channel: {channel}").WithParams(apperr.Params{"channel": p.Channel}))
+			return
+		}
+		if i, ok := seen[[2]string{p.Category, p.Channel}]; ok {
+			preferences[i].Enabled = *p.Enabled
+			continue
+		}
+		seen[[2]string{p.Category, p.Channel}] = len(preferences)
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:   currentUserID,
+			Category: p.Category,
+			Channel:  p.Channel,
+			Enabled:  *p.Enabled,
+		})
+	}
+
+	if len(preferences) > 0 {
+		if err := config.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).Create(&preferences).Error; err != nil {
+			apperr.Abort(c, apperr.Internal("Failed to update preferences").Wrap(err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification preferences updated"})
+}
+
+// CreateNotification delivers a notification of the given category to a user on every channel they have enabled
+func CreateNotification(userID uuid.UUID, depositID *uuid.UUID, templateKey string, params map[string]string, notifType string) error {
+	// Title and message are stored in the default locale for clients that ignore the template
 	notification := models.Notification{
-		UserID:    userID,
-		DepositID: depositID,
-		Title:     title,
-		Message:   message,
-		Type:      notifType,
-		IsRead:    false,
-	}
-	return config.DB.Create(&notification).Error
+		UserID:         userID,
+		DepositID:      depositID,
+		Title:          i18n.T(i18n.Default, templateKey+".title", params),
+		Message:        i18n.T(i18n.Default, templateKey+".message", params),
+		TemplateKey:    templateKey,
+		TemplateParams: params,
+		Type:           notifType,
+		IsRead:         false,
+	}
+
+	if notificationEnabled(userID, notifType, "in_app") {
+		if err := config.DB.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(notificationSenders) == 0 {
+		return nil
+	}
+
+	var user models.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return err
+	}
+
+	sendExternalNotification(user, notification)
+	return nil
+}
+
+// localizeNotification renders a templated notification in locale; free-text notifications are returned as stored
+func localizeNotification(locale string, notification models.Notification) models.Notification {
+	if notification.TemplateKey != "" {
+		notification.Title = i18n.T(locale, notification.TemplateKey+".title", notification.TemplateParams)
+		notification.Message = i18n.T(locale, notification.TemplateKey+".message", notification.TemplateParams)
+	}
+	return notification
+}
+
+func localizeNotifications(locale string, notifications []models.Notification) []models.Notification {
+	for i := range notifications {
+		notifications[i] = localizeNotification(locale, notifications[i])
+	}
+	return notifications
+}
+
+// sendExternalNotification delivers on every registered non in-app channel the user has enabled.
+// External channels are best effort and must not fail the request that triggered them.
+// Text is rendered in the user's saved locale.
+func sendExternalNotification(user models.User, notification models.Notification) {
+	locale := user.Locale
+	if !i18n.Supported(locale) {
+		locale = i18n.Default
+	}
+	notification = localizeNotification(locale, notification)
+
+	for channel, sender := range notificationSenders {
+		if !notificationEnabled(user.ID, notification.Type, channel) {
+			continue
+		}
+		if err := sender.Send(user, notification); err != nil {
+			log.Printf("Failed to send %s notification to %s: %v", channel, user.ID, err)
+		}
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// StartNotificationRetention purges read notifications older than NOTIFICATION_RETENTION_DAYS (default 90)
+func StartNotificationRetention(interval time.Duration) {
+	go func() {
+		// Run once at startup, then on every tick
+		purgeReadNotifications()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			purgeReadNotifications()
+		}
+	}()
+}
+
+func purgeReadNotifications() {
+	days, err := strconv.Atoi(os.Getenv("NOTIFICATION_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = 90
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	result := config.DB.Where("is_read = ? AND created_at < ?", true, cutoff).Delete(&models.Notification{})
+	if result.Error != nil {
+		log.Println("Notification retention:", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Notification retention: purged %d read notifications older than %d days", result.RowsAffected, days)
+	}
 }
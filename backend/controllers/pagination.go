@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePagination reads ?page= and ?limit= from the query string.
+// On invalid input it writes a 400 response and returns ok=false.
+func parsePagination(c *gin.Context) (page, limit int, ok bool) {
+	page, limit = 1, defaultPageSize
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		parsed, err := strconv.Atoi(pageStr)
+		if err != nil || parsed < 1 {
+			apperr.Abort(c, apperr.BadRequest("Invalid page"))
+			return 0, 0, false
+		}
+		page = parsed
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			apperr.Abort(c, apperr.BadRequest("Invalid limit"))
+			return 0, 0, false
+		}
+		if parsed > maxPageSize {
+			parsed = maxPageSize
+		}
+		limit = parsed
+	}
+
+	return page, limit, true
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"backend-api/storage"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// photoURLExpiry returns how long signed photo URLs stay valid, from PHOTO_URL_EXPIRY_MINUTES (default 15)
+func photoURLExpiry() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PHOTO_URL_EXPIRY_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// signURL turns a stored file URL into a short-lived signed URL.
+// URLs that don't belong to the storage backend are returned unchanged.
+func signURL(ctx context.Context, stored string) string {
+	key, ok := storage.Files.Key(stored)
+	if !ok {
+		return stored
+	}
+	signed, err := storage.Files.SignedURL(ctx, key, photoURLExpiry())
+	if err != nil {
+		return stored
+	}
+	return signed
+}
+
+// withSignedPhoto returns a copy of the deposit with signed photo and thumbnail URLs for the response
+func withSignedPhoto(ctx context.Context, deposit models.WasteDeposit) models.WasteDeposit {
+	if deposit.PhotoProof != "" {
+		deposit.PhotoProof = signURL(ctx, deposit.PhotoProof)
+	}
+	if len(deposit.PhotoThumbnails) > 0 {
+		thumbnails := make(map[string]string, len(deposit.PhotoThumbnails))
+		for size, u := range deposit.PhotoThumbnails {
+			thumbnails[size] = signURL(ctx, u)
+		}
+		deposit.PhotoThumbnails = thumbnails
+	}
+	return deposit
+}
+
+func withSignedPhotos(ctx context.Context, deposits []models.WasteDeposit) []models.WasteDeposit {
+	signed := make([]models.WasteDeposit, len(deposits))
+	for i, d := range deposits {
+		signed[i] = withSignedPhoto(ctx, d)
+	}
+	return signed
+}
+
+// withSignedAttachment returns a copy of the message with signed attachment and deposit photo URLs
+func withSignedAttachment(ctx context.Context, message models.ChatMessage) models.ChatMessage {
+	if message.Attachment != "" {
+		message.Attachment = signURL(ctx, message.Attachment)
+	}
+	if message.Deposit != nil {
+		deposit := withSignedPhoto(ctx, *message.Deposit)
+		message.Deposit = &deposit
+	}
+	return message
+}
+
+func withSignedAttachments(ctx context.Context, messages []models.ChatMessage) []models.ChatMessage {
+	signed := make([]models.ChatMessage, len(messages))
+	for i, m := range messages {
+		signed[i] = withSignedAttachment(ctx, m)
+	}
+	return signed
+}
+
+// canViewDeposit reports whether the user is the deposit owner, its assigned picker, or an admin or
+// coordinator of its school. GetDepositByID and the photo endpoints share it so they admit the same users.
+func canViewDeposit(userID uuid.UUID, deposit models.WasteDeposit) bool {
+	if deposit.UserID == userID || (deposit.PickerID != nil && *deposit.PickerID == userID) {
+		return true
+	}
+	return deposit.SchoolID != nil && canManageSchool(userID, *deposit.SchoolID)
+}
+
+// GetDepositPhoto streams a deposit's proof photo (or ?size=small|medium thumbnail) to an authorised viewer
+func GetDepositPhoto(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	// Respond 404 rather than 403 so deposit IDs can't be probed
+	if !canViewDeposit(userID.(uuid.UUID), deposit) {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	stored := deposit.PhotoProof
+	if size := c.Query("size"); size != "" {
+		stored = deposit.PhotoThumbnails[size]
+	}
+	if stored == "" {
+		apperr.Abort(c, apperr.NotFound("Photo not found"))
+		return
+	}
+
+	key, ok := storage.Files.Key(stored)
+	if !ok {
+		apperr.Abort(c, apperr.NotFound("Photo not found"))
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	serveStoredFile(c, key)
+}
+
+// GetDepositPhotoURL returns short-lived signed URLs for a deposit's photo and thumbnails
+func GetDepositPhotoURL(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	if !canViewDeposit(userID.(uuid.UUID), deposit) {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	if deposit.PhotoProof == "" {
+		apperr.Abort(c, apperr.NotFound("Photo not found"))
+		return
+	}
+
+	signed := withSignedPhoto(c.Request.Context(), deposit)
+	c.JSON(http.StatusOK, gin.H{
+		"photo_url":        signed.PhotoProof,
+		"photo_thumbnails": signed.PhotoThumbnails,
+		"expires_at":       time.Now().Add(photoURLExpiry()).In(jakartaLoc),
+	})
+}
+
+// ServeUpload serves files from local storage. Profile pictures are public,
+// everything else (deposit photos, chat attachments) needs a valid signed URL.
+func ServeUpload(c *gin.Context) {
+	local, ok := storage.Files.(*storage.Local)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	key := strings.TrimPrefix(path.Clean(c.Param("filepath")), "/")
+	if !strings.HasPrefix(key, "profiles/") && !local.VerifySignature(key, c.Query("expires"), c.Query("signature")) {
+		apperr.Abort(c, apperr.Forbidden("Invalid or expired link"))
+		return
+	}
+
+	serveStoredFile(c, key)
+}
+
+func serveStoredFile(c *gin.Context, key string) {
+	file, err := storage.Files.Get(c.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		apperr.Abort(c, apperr.NotFound("File not found"))
+		return
+	}
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to read file").Wrap(err))
+		return
+	}
+	defer file.Close()
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	if rs, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), time.Time{}, rs)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, -1, contentTypeFor(key), file, nil)
+}
+
+func contentTypeFor(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".png":
+		return "image/png"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".webp":
+		return "image/webp"
+	}
+	return "application/octet-stream"
+}
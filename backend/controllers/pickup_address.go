@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"backend-api/utils"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var errPickupAddressNotFound = errors.New("pickup address not found")
+
+type PickupAddressInput struct {
+	Label        string   `json:"label" binding:"required"`
+	Address      string   `json:"address" binding:"required"`
+	ContactName  string   `json:"contact_name" binding:"required"`
+	ContactPhone string   `json:"contact_phone" binding:"required"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	IsDefault    bool     `json:"is_default"`
+}
+
+// currentSchoolUser loads the authenticated user and requires them to belong to a school.
+// On failure it writes the response and returns ok=false.
+func currentSchoolUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return user, false
+	}
+
+	if err := config.DB.Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("User not found"))
+		return user, false
+	}
+	if user.SchoolID == nil {
+		if user.PendingSchoolID != nil || user.RequestedSchool != "" {
+			apperr.Abort(c, apperr.BadRequest("Your request to join the school is waiting for approval").WithCode(apperr.CodeSchoolRequired))
+			return user, false
+		}
+		apperr.Abort(c, apperr.BadRequest("Set your school in your profile first").WithCode(apperr.CodeSchoolRequired))
+		return user, false
+	}
+	return user, true
+}
+
+// findPickupAddress returns a saved address of the school, or its default address when id is empty.
+// Returns nil without error when the school has no default address.
+func findPickupAddress(schoolID uuid.UUID, id string) (*models.PickupAddress, error) {
+	var address models.PickupAddress
+	query := config.DB.Where("school_id = ?", schoolID)
+	if id != "" {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, errPickupAddressNotFound
+		}
+		query = query.Where("id = ?", id)
+	} else {
+		query = query.Where("is_default = ?", true)
+	}
+
+	if err := query.First(&address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if id == "" {
+				return nil, nil
+			}
+			return nil, errPickupAddressNotFound
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+// saveDefaultPickupAddress saves the address and, when it is the default, clears the flag on the school's other addresses
+func saveDefaultPickupAddress(address *models.PickupAddress) error {
+	tx := config.DB.Begin()
+	if address.IsDefault {
+		if err := tx.Model(&models.PickupAddress{}).
+			Where("school_id = ? AND id <> ? AND is_default = ?", address.SchoolID, address.ID, true).
+			Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Save(address).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func applyPickupAddressInput(address *models.PickupAddress, input PickupAddressInput) {
+	address.Label = strings.TrimSpace(input.Label)
+	address.Address = strings.TrimSpace(input.Address)
+	address.ContactName = strings.TrimSpace(input.ContactName)
+	address.ContactPhone = strings.TrimSpace(input.ContactPhone)
+	address.Latitude = input.Latitude
+	address.Longitude = input.Longitude
+	address.IsDefault = input.IsDefault
+}
+
+// GetPickupAddresses lists the saved pickup addresses of the user's school, default first
+func GetPickupAddresses(c *gin.Context) {
+	user, ok := currentSchoolUser(c)
+	if !ok {
+		return
+	}
+
+	var addresses []models.PickupAddress
+	if err := config.DB.Where("school_id = ?", *user.SchoolID).Order("is_default DESC, label").Find(&addresses).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch pickup addresses").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"addresses": addresses})
+}
+
+// CreatePickupAddress saves a pickup address for the user's school. The first address becomes the default.
+func CreatePickupAddress(c *gin.Context) {
+	user, ok := currentSchoolUser(c)
+	if !ok {
+		return
+	}
+
+	var input PickupAddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	phone, err := utils.NormalizePhone(input.ContactPhone)
+	if err != nil {
+		apperr.Abort(c, apperr.Validation(apperr.Fields{"contact_phone": "must be an Indonesian phone number, e.g. 081234567890"}))
+		return
+	}
+	input.ContactPhone = phone
+
+	address := models.PickupAddress{
+		SchoolID:  *user.SchoolID,
+		CreatedBy: user.ID,
+	}
+	applyPickupAddressInput(&address, input)
+
+	var existing int64
+	config.DB.Model(&models.PickupAddress{}).Where("school_id = ?", address.SchoolID).Count(&existing)
+	if existing == 0 {
+		address.IsDefault = true
+	}
+
+	if err := config.DB.Create(&address).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to create pickup address").Wrap(err))
+		return
+	}
+	if address.IsDefault && existing > 0 {
+		if err := saveDefaultPickupAddress(&address); err != nil {
+			apperr.Abort(c, apperr.Internal("Failed to set default pickup address").Wrap(err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Pickup address created successfully",
+		"address": address,
+	})
+}
+
+// UpdatePickupAddress edits a saved address (its creator or a school coordinator/admin)
+func UpdatePickupAddress(c *gin.Context) {
+	user, ok := currentSchoolUser(c)
+	if !ok {
+		return
+	}
+
+	address, err := findPickupAddress(*user.SchoolID, c.Param("id"))
+	if err != nil {
+		apperr.Abort(c, apperr.NotFound("Pickup address not found"))
+		return
+	}
+	if address.CreatedBy != user.ID && !canManageSchool(user.ID, address.SchoolID) {
+		apperr.Abort(c, apperr.Forbidden("You can only edit addresses you created"))
+		return
+	}
+
+	var input PickupAddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	phone, err := utils.NormalizePhone(input.ContactPhone)
+	if err != nil {
+		apperr.Abort(c, apperr.Validation(apperr.Fields{"contact_phone": "must be an Indonesian phone number, e.g. 081234567890"}))
+		return
+	}
+	input.ContactPhone = phone
+
+	// The default can be moved to another address but not simply switched off
+	wasDefault := address.IsDefault
+	applyPickupAddressInput(address, input)
+	if wasDefault {
+		address.IsDefault = true
+	}
+
+	if err := saveDefaultPickupAddress(address); err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update pickup address").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pickup address updated successfully",
+		"address": address,
+	})
+}
+
+// DeletePickupAddress removes a saved address. Deposits keep their copied address.
+// When the default is deleted the most recently created remaining address takes over.
+func DeletePickupAddress(c *gin.Context) {
+	user, ok := currentSchoolUser(c)
+	if !ok {
+		return
+	}
+
+	address, err := findPickupAddress(*user.SchoolID, c.Param("id"))
+	if err != nil {
+		apperr.Abort(c, apperr.NotFound("Pickup address not found"))
+		return
+	}
+	if address.CreatedBy != user.ID && !canManageSchool(user.ID, address.SchoolID) {
+		apperr.Abort(c, apperr.Forbidden("You can only delete addresses you created"))
+		return
+	}
+
+	if err := config.DB.Delete(address).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to delete pickup address").Wrap(err))
+		return
+	}
+
+	if address.IsDefault {
+		var next models.PickupAddress
+		if err := config.DB.Where("school_id = ?", address.SchoolID).Order("created_at DESC").First(&next).Error; err == nil {
+			config.DB.Model(&next).Update("is_default", true)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pickup address deleted successfully"})
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"backend-api/config"
+	"backend-api/models"
+	"log"
+	"math"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// depositPoints returns the points a deposit is worth in its current state
+func depositPoints(deposit models.WasteDeposit) int {
+	if deposit.Status != "completed" || deposit.Weight == nil {
+		return 0
+	}
+	return int(math.Round(*deposit.Weight * config.PointsPerKg()))
+}
+
+// syncDepositPoints adds a ledger entry so the points recorded for the deposit match its current
+// status and weight. Earlier entries are never modified. Returns the change in points.
+func syncDepositPoints(tx *gorm.DB, deposit models.WasteDeposit) (int, error) {
+	var recorded int
+	if err := tx.Model(&models.PointTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("deposit_id = ?", deposit.ID).
+		Scan(&recorded).Error; err != nil {
+		return 0, err
+	}
+
+	delta := depositPoints(deposit) - recorded
+	if delta == 0 {
+		return 0, nil
+	}
+
+	reason := "deposit_adjusted"
+	if recorded == 0 && delta > 0 {
+		reason = "deposit_completed"
+	}
+
+	entry := models.PointTransaction{
+		UserID:    deposit.UserID,
+		DepositID: &deposit.ID,
+		Points:    delta,
+		Reason:    reason,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
+
+// userPoints returns the user's current point balance
+func userPoints(userID uuid.UUID) int {
+	var total int
+	config.DB.Model(&models.PointTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("user_id = ?", userID).
+		Scan(&total)
+	return total
+}
+
+// schoolPoints is the sum of the points earned by the deposits of a school
+func schoolPoints(schoolID uuid.UUID) int {
+	var total int
+	config.DB.Model(&models.PointTransaction{}).
+		Select("COALESCE(SUM(point_transactions.points), 0)").
+		Joins("JOIN waste_deposits ON waste_deposits.id = point_transactions.deposit_id").
+		Where("waste_deposits.school_id = ?", schoolID).
+		Scan(&total)
+	return total
+}
+
+// BackfillDepositPoints records points for completed deposits that predate the ledger
+func BackfillDepositPoints() {
+	var deposits []models.WasteDeposit
+	if err := config.DB.
+		Where("status = ? AND weight IS NOT NULL", "completed").
+		Where("NOT EXISTS (SELECT 1 FROM point_transactions pt WHERE pt.deposit_id = waste_deposits.id)").
+		Find(&deposits).Error; err != nil {
+		log.Println("Point backfill:", err)
+		return
+	}
+
+	for _, d := range deposits {
+		if _, err := syncDepositPoints(config.DB, d); err != nil {
+			log.Printf("Point backfill: deposit %s: %v", d.ID, err)
+		}
+	}
+	if len(deposits) > 0 {
+		log.Printf("Point backfill: recorded points for %d deposits", len(deposits))
+	}
+}
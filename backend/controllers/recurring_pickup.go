@@ -0,0 +1,550 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"backend-api/utils"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Number of upcoming occurrences shown per recurring pickup
+const upcomingOccurrenceCount = 4
+
+type RecurringPickupInput struct {
+	Weekday         *int        `json:"weekday" binding:"required,min=0,max=6"` // 0 = Sunday
+	IntervalWeeks   int         `json:"interval_weeks" binding:"omitempty,min=1,max=4"`
+	WasteType       string      `json:"waste_type" binding:"required,max=50"`
+	BinCount        json.Number `json:"bin_count" binding:"required"`
+	PickupAddressID string      `json:"pickup_address_id"`
+	Address         string      `json:"address" binding:"max=500"`
+	ContactName     string      `json:"contact_name" binding:"max=100"`
+	ContactPhone    string      `json:"contact_phone" binding:"max=30"`
+	StartDate       string      `json:"start_date"` // DD/MM/YYYY, default today
+}
+
+type recurringOccurrence struct {
+	Date    string `json:"date"` // YYYY-MM-DD
+	Skipped bool   `json:"skipped"`
+	Holiday bool   `json:"holiday"`
+}
+
+// recurringLeadDays is how far ahead deposits are generated, from RECURRING_PICKUP_LEAD_DAYS (default 7)
+func recurringLeadDays() int {
+	days, err := strconv.Atoi(os.Getenv("RECURRING_PICKUP_LEAD_DAYS"))
+	if err != nil || days < 1 {
+		return 7
+	}
+	return days
+}
+
+// pickupDay returns the Jakarta calendar day of t at midnight UTC, the form pickup dates are stored in
+func pickupDay(t time.Time) time.Time {
+	t = t.In(jakartaLoc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// firstOccurrence returns the first date on or after from falling on weekday
+func firstOccurrence(from time.Time, weekday int) time.Time {
+	offset := (weekday - int(from.Weekday()) + 7) % 7
+	return from.AddDate(0, 0, offset)
+}
+
+// upcomingOccurrences lists the next dates of a schedule from its next ungenerated date
+func upcomingOccurrences(pickup models.RecurringPickup, holidays map[string]bool) []recurringOccurrence {
+	occurrences := make([]recurringOccurrence, 0, upcomingOccurrenceCount)
+	date := pickup.NextDate
+	for i := 0; i < upcomingOccurrenceCount; i++ {
+		day := date.Format("2006-01-02")
+		occurrences = append(occurrences, recurringOccurrence{
+			Date:    day,
+			Skipped: containsString(pickup.SkipDates, day),
+			Holiday: holidays[day],
+		})
+		date = date.AddDate(0, 0, 7*pickup.IntervalWeeks)
+	}
+	return occurrences
+}
+
+func recurringPickupResponse(pickup models.RecurringPickup, holidays map[string]bool) gin.H {
+	return gin.H{
+		"recurring_pickup": pickup,
+		"upcoming":         upcomingOccurrences(pickup, holidays),
+	}
+}
+
+// applyRecurringPickupInput validates the input against the user's school and copies it onto the schedule.
+// Uses the same rules as a single deposit, so every generated deposit would have passed them too.
+func applyRecurringPickupInput(pickup *models.RecurringPickup, input RecurringPickupInput) *apperr.Error {
+	fields := apperr.Fields{}
+	params := apperr.FieldParams{}
+
+	binCount, msg, binParams := validateBinCount(input.BinCount)
+	if msg != "" {
+		fields["bin_count"], params["bin_count"] = msg, binParams
+	}
+	wasteType := strings.TrimSpace(input.WasteType)
+	if wasteType == "" {
+		fields["waste_type"] = "is required"
+	}
+	phone := ""
+	if contactPhone := strings.TrimSpace(input.ContactPhone); contactPhone != "" {
+		var err error
+		if phone, err = utils.NormalizePhone(contactPhone); err != nil {
+			fields["contact_phone"] = "must be an Indonesian phone number, e.g. 081234567890"
+		}
+	}
+	if len(fields) > 0 {
+		return apperr.Validation(fields).WithFieldParams(params)
+	}
+
+	pickup.Weekday = *input.Weekday
+	pickup.IntervalWeeks = input.IntervalWeeks
+	if pickup.IntervalWeeks == 0 {
+		pickup.IntervalWeeks = 1
+	}
+	pickup.WasteType = wasteType
+	pickup.BinCount = binCount
+	pickup.Address = strings.TrimSpace(input.Address)
+	pickup.ContactName = strings.TrimSpace(input.ContactName)
+	pickup.ContactPhone = phone
+	pickup.PickupAddressID = nil
+
+	// Without a typed address the given saved address, or else the school's default, is used
+	if input.PickupAddressID != "" || pickup.Address == "" {
+		saved, err := findPickupAddress(pickup.SchoolID, input.PickupAddressID)
+		if err != nil {
+			return apperr.BadRequest("Pickup address not found")
+		}
+		if saved != nil {
+			pickup.PickupAddressID = &saved.ID
+			return nil
+		}
+	}
+
+	if pickup.Address == "" || pickup.ContactName == "" || pickup.ContactPhone == "" {
+		return apperr.BadRequest("pickup_address_id or address, contact_name and contact_phone are required")
+	}
+	return nil
+}
+
+// GetRecurringPickups lists the user's recurring pickup schedules with their upcoming dates
+func GetRecurringPickups(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var pickups []models.RecurringPickup
+	if err := config.DB.Where("user_id = ?", userID.(uuid.UUID)).Order("created_at DESC").Find(&pickups).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch recurring pickups").Wrap(err))
+		return
+	}
+
+	holidays := config.Holidays()
+	result := make([]gin.H, len(pickups))
+	for i, p := range pickups {
+		result[i] = recurringPickupResponse(p, holidays)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recurring_pickups": result})
+}
+
+// CreateRecurringPickup creates a schedule for the user's school and generates any deposits due within the lead window
+func CreateRecurringPickup(c *gin.Context) {
+	user, ok := currentSchoolUser(c)
+	if !ok {
+		return
+	}
+
+	var input RecurringPickupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	start := pickupDay(time.Now())
+	if input.StartDate != "" {
+		parsed, err := time.Parse("02/01/2006", input.StartDate)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid start_date format. Use DD/MM/YYYY"))
+			return
+		}
+		if parsed.After(start) {
+			start = parsed
+		}
+	}
+
+	pickup := models.RecurringPickup{
+		UserID:   user.ID,
+		SchoolID: *user.SchoolID,
+	}
+	if appErr := applyRecurringPickupInput(&pickup, input); appErr != nil {
+		apperr.Abort(c, appErr)
+		return
+	}
+	pickup.NextDate = firstOccurrence(start, pickup.Weekday)
+
+	if err := config.DB.Create(&pickup).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to create recurring pickup").Wrap(err))
+		return
+	}
+
+	holidays := config.Holidays()
+	generateRecurringDeposits(&pickup, holidays)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":          "Recurring pickup created successfully",
+		"recurring_pickup": pickup,
+		"upcoming":         upcomingOccurrences(pickup, holidays),
+	})
+}
+
+// findOwnRecurringPickup loads a schedule owned by the authenticated user, writing a 404 when missing
+func findOwnRecurringPickup(c *gin.Context) (*models.RecurringPickup, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return nil, false
+	}
+
+	var pickup models.RecurringPickup
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID.(uuid.UUID)).First(&pickup).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Recurring pickup not found"))
+		return nil, false
+	}
+	return &pickup, true
+}
+
+// UpdateRecurringPickup changes a schedule. Deposits already generated are kept as they are.
+func UpdateRecurringPickup(c *gin.Context) {
+	pickup, ok := findOwnRecurringPickup(c)
+	if !ok {
+		return
+	}
+
+	var input RecurringPickupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	if appErr := applyRecurringPickupInput(pickup, input); appErr != nil {
+		apperr.Abort(c, appErr)
+		return
+	}
+	pickup.NextDate = firstOccurrence(pickup.NextDate, pickup.Weekday)
+
+	if err := config.DB.Save(pickup).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update recurring pickup").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Recurring pickup updated successfully",
+		"recurring_pickup": pickup,
+		"upcoming":         upcomingOccurrences(*pickup, config.Holidays()),
+	})
+}
+
+// DeleteRecurringPickup removes a schedule along with its generated deposits that are still pending
+func DeleteRecurringPickup(c *gin.Context) {
+	pickup, ok := findOwnRecurringPickup(c)
+	if !ok {
+		return
+	}
+
+	tx := config.DB.Begin()
+	if err := tx.Where("recurring_pickup_id = ? AND status = ? AND pickup_date >= ?", pickup.ID, "pending", pickupDay(time.Now())).
+		Delete(&models.WasteDeposit{}).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to delete recurring pickup"))
+		return
+	}
+	if err := tx.Delete(pickup).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to delete recurring pickup"))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to delete recurring pickup").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recurring pickup deleted successfully"})
+}
+
+// PauseRecurringPickup stops generating deposits until the schedule is resumed
+func PauseRecurringPickup(c *gin.Context) {
+	pickup, ok := findOwnRecurringPickup(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Model(pickup).Update("paused", true).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to pause recurring pickup").Wrap(err))
+		return
+	}
+	pickup.Paused = true
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recurring pickup paused", "recurring_pickup": pickup})
+}
+
+// ResumeRecurringPickup restarts a paused schedule from its next date on or after today.
+// Dates missed while paused are not generated.
+func ResumeRecurringPickup(c *gin.Context) {
+	pickup, ok := findOwnRecurringPickup(c)
+	if !ok {
+		return
+	}
+
+	today := pickupDay(time.Now())
+	for pickup.NextDate.Before(today) {
+		pickup.NextDate = pickup.NextDate.AddDate(0, 0, 7*pickup.IntervalWeeks)
+	}
+	pickup.Paused = false
+
+	if err := config.DB.Model(pickup).Updates(map[string]interface{}{
+		"paused":    false,
+		"next_date": pickup.NextDate,
+	}).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to resume recurring pickup").Wrap(err))
+		return
+	}
+
+	holidays := config.Holidays()
+	generateRecurringDeposits(pickup, holidays)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Recurring pickup resumed",
+		"recurring_pickup": pickup,
+		"upcoming":         upcomingOccurrences(*pickup, holidays),
+	})
+}
+
+// SkipRecurringOccurrence skips a single date of a schedule.
+// If the deposit for that date was already generated and is still pending it is removed.
+func SkipRecurringOccurrence(c *gin.Context) {
+	pickup, ok := findOwnRecurringPickup(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Date string `json:"date" binding:"required"` // DD/MM/YYYY
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	date, err := time.Parse("02/01/2006", input.Date)
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid date format. Use DD/MM/YYYY"))
+		return
+	}
+	if int(date.Weekday()) != pickup.Weekday || date.Before(pickupDay(time.Now())) {
+		apperr.Abort(c, apperr.BadRequest("Date is not an upcoming occurrence of this schedule"))
+		return
+	}
+
+	day := date.Format("2006-01-02")
+	if !containsString(pickup.SkipDates, day) {
+		pickup.SkipDates = append(pickup.SkipDates, day)
+	}
+
+	tx := config.DB.Begin()
+	if err := tx.Model(pickup).Update("skip_dates", pickup.SkipDates).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to skip occurrence"))
+		return
+	}
+	removed := tx.Where("recurring_pickup_id = ? AND status = ? AND pickup_date = ?", pickup.ID, "pending", date).
+		Delete(&models.WasteDeposit{})
+	if removed.Error != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to skip occurrence"))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to skip occurrence").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Occurrence skipped",
+		"deposit_removed":  removed.RowsAffected > 0,
+		"recurring_pickup": pickup,
+		"upcoming":         upcomingOccurrences(*pickup, config.Holidays()),
+	})
+}
+
+// StartRecurringPickupScheduler periodically generates deposits for active schedules
+func StartRecurringPickupScheduler(interval time.Duration) {
+	go func() {
+		// Run once at startup, then on every tick
+		runRecurringPickups()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runRecurringPickups()
+		}
+	}()
+}
+
+func runRecurringPickups() {
+	until := pickupDay(time.Now()).AddDate(0, 0, recurringLeadDays())
+
+	var pickups []models.RecurringPickup
+	if err := config.DB.Where("paused = ? AND next_date <= ?", false, until).Find(&pickups).Error; err != nil {
+		log.Println("Recurring pickups:", err)
+		return
+	}
+
+	holidays := config.Holidays()
+	for i := range pickups {
+		generateRecurringDeposits(&pickups[i], holidays)
+	}
+}
+
+// generateRecurringDeposits creates pending deposits for every occurrence within the lead window.
+// Moving next_date forward is the claim for an occurrence and happens in the same transaction as the
+// deposit, so a date is never generated twice and is not lost when the deposit can't be created.
+// Past dates, skipped dates and holidays are passed over without a deposit.
+func generateRecurringDeposits(pickup *models.RecurringPickup, holidays map[string]bool) {
+	if pickup.Paused {
+		return
+	}
+
+	today := pickupDay(time.Now())
+	until := today.AddDate(0, 0, recurringLeadDays())
+	for !pickup.NextDate.After(until) {
+		date := pickup.NextDate
+		next := date.AddDate(0, 0, 7*pickup.IntervalWeeks)
+		day := date.Format("2006-01-02")
+		skip := date.Before(today) || holidays[day] || containsString(pickup.SkipDates, day)
+
+		updates := map[string]interface{}{"next_date": next}
+		if !skip {
+			updates["last_error"] = ""
+			updates["last_error_at"] = nil
+		}
+
+		tx := config.DB.Begin()
+		claim := tx.Model(&models.RecurringPickup{}).
+			Where("id = ? AND next_date = ?", pickup.ID, date).
+			Updates(updates)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			tx.Rollback()
+			return
+		}
+
+		var deposit *models.WasteDeposit
+		if !skip {
+			created, err := createRecurringDeposit(tx, *pickup, date)
+			if err != nil {
+				tx.Rollback()
+				recordRecurringFailure(pickup, date, err)
+				return
+			}
+			deposit = created
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			log.Printf("Recurring pickup %s: failed to save %s: %v", pickup.ID, day, err)
+			return
+		}
+		pickup.NextDate = next
+
+		if deposit != nil {
+			pickup.LastError, pickup.LastErrorAt = "", nil
+			params := depositNotificationParams(*deposit)
+			params["date"] = date.Format("02/01/2006")
+			CreateNotification(deposit.UserID, &deposit.ID, "deposit.recurring", params, "deposit_status")
+		}
+	}
+}
+
+// recordRecurringFailure stores why an occurrence could not be generated. The occurrence stays due and is
+// retried on the next run; the owner is notified once when the schedule starts failing, not on every retry.
+func recordRecurringFailure(pickup *models.RecurringPickup, date time.Time, cause error) {
+	log.Printf("Recurring pickup %s: failed to create deposit for %s: %v", pickup.ID, date.Format("2006-01-02"), cause)
+
+	firstFailure := pickup.LastError == ""
+	now := time.Now().In(jakartaLoc)
+	if err := config.DB.Model(&models.RecurringPickup{}).Where("id = ?", pickup.ID).
+		Updates(map[string]interface{}{"last_error": cause.Error(), "last_error_at": now}).Error; err != nil {
+		log.Printf("Recurring pickup %s: failed to record error: %v", pickup.ID, err)
+	}
+	pickup.LastError, pickup.LastErrorAt = cause.Error(), &now
+
+	if firstFailure {
+		CreateNotification(pickup.UserID, nil, "deposit.recurring_failed", map[string]string{
+			"waste_type": pickup.WasteType,
+			"bin_count":  strconv.Itoa(pickup.BinCount),
+			"date":       date.Format("02/01/2006"),
+		}, "deposit_status")
+	}
+}
+
+func createRecurringDeposit(tx *gorm.DB, pickup models.RecurringPickup, date time.Time) (*models.WasteDeposit, error) {
+	var school models.School
+	if err := tx.Where("id = ?", pickup.SchoolID).First(&school).Error; err != nil {
+		return nil, fmt.Errorf("school %s: %w", pickup.SchoolID, err)
+	}
+
+	deposit := models.WasteDeposit{
+		UserID:            pickup.UserID,
+		SchoolID:          &school.ID,
+		SchoolName:        school.Name,
+		ContactName:       pickup.ContactName,
+		ContactPhone:      pickup.ContactPhone,
+		Address:           pickup.Address,
+		PickupDate:        date,
+		BinCount:          pickup.BinCount,
+		WasteType:         pickup.WasteType,
+		Status:            "pending",
+		RecurringPickupID: &pickup.ID,
+	}
+
+	// The saved address is copied as it is now; if it was deleted the school's default is used instead
+	if pickup.PickupAddressID != nil || pickup.Address == "" {
+		id := ""
+		if pickup.PickupAddressID != nil {
+			id = pickup.PickupAddressID.String()
+		}
+		saved, err := findPickupAddress(school.ID, id)
+		if err != nil && id != "" {
+			saved, err = findPickupAddress(school.ID, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if saved == nil {
+			return nil, fmt.Errorf("school %s has no pickup address", school.ID)
+		}
+		deposit.PickupAddressID = &saved.ID
+		deposit.Address = saved.Address
+		deposit.ContactName = saved.ContactName
+		deposit.ContactPhone = saved.ContactPhone
+		deposit.Latitude = saved.Latitude
+		deposit.Longitude = saved.Longitude
+	}
+
+	if err := tx.Create(&deposit).Error; err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
@@ -0,0 +1,350 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"backend-api/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+)
+
+var indonesianMonths = []string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// monthlyReportKey is where a pre-built report is kept in storage
+func monthlyReportKey(school models.School, month time.Time) string {
+	return fmt.Sprintf("reports/%s/%s.pdf", school.ID, month.Format("2006-01"))
+}
+
+// monthlyReportFilename is the download name, e.g. laporan-sman-1-2024-05.pdf
+func monthlyReportFilename(school models.School, month time.Time) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(school.Name), "-"), "-")
+	return fmt.Sprintf("laporan-%s-%s.pdf", slug, month.Format("2006-01"))
+}
+
+// parseReportMonth reads ?month=YYYY-MM, defaulting to the previous month
+func parseReportMonth(c *gin.Context) (time.Time, bool) {
+	now := time.Now().In(jakartaLoc)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, jakartaLoc).AddDate(0, -1, 0)
+	if monthStr := c.Query("month"); monthStr != "" {
+		parsed, err := time.ParseInLocation("2006-01", monthStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid month. Use YYYY-MM"))
+			return month, false
+		}
+		month = parsed
+	}
+	return month, true
+}
+
+// buildMonthlyReport renders the PDF listing a school's completed deposits picked up in the month
+func buildMonthlyReport(school models.School, month time.Time) ([]byte, error) {
+	start := month
+	end := month.AddDate(0, 1, 0)
+
+	var deposits []models.WasteDeposit
+	if err := config.DB.
+		Where("school_id = ? AND status = ? AND pickup_date >= ? AND pickup_date < ?", school.ID, "completed", start, end).
+		Order("pickup_date ASC").
+		Find(&deposits).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(deposits))
+	for i, d := range deposits {
+		ids[i] = d.ID
+	}
+
+	var pointRows []struct {
+		DepositID uuid.UUID
+		Points    int
+	}
+	if len(ids) > 0 {
+		if err := config.DB.Model(&models.PointTransaction{}).
+			Select("deposit_id, SUM(points) AS points").
+			Where("deposit_id IN ?", ids).
+			Group("deposit_id").
+			Scan(&pointRows).Error; err != nil {
+			return nil, err
+		}
+	}
+	pointsByDeposit := make(map[uuid.UUID]int, len(pointRows))
+	for _, p := range pointRows {
+		pointsByDeposit[p.DepositID] = p.Points
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Laporan Bulanan "+school.Name, true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Lumbung Hijau - dibuat %s - halaman %d", time.Now().In(jakartaLoc).Format("02/01/2006 15:04"), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Laporan Bulanan Penyetoran Sampah", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 7, tr("Sekolah: "+school.Name), "", 1, "L", false, 0, "")
+	if school.NPSN != nil {
+		pdf.CellFormat(0, 7, "NPSN: "+*school.NPSN, "", 1, "L", false, 0, "")
+	}
+	if school.Address != "" {
+		pdf.CellFormat(0, 7, tr("Alamat: "+school.Address), "", 1, "L", false, 0, "")
+	}
+	pdf.CellFormat(0, 7, fmt.Sprintf("Periode: %s %d", indonesianMonths[month.Month()-1], month.Year()), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	// Deposit table
+	widths := []float64{10, 28, 50, 20, 30, 25}
+	headers := []string{"No", "Tanggal", "Jenis Sampah", "Tong", "Berat (Kg)", "Poin"}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(220, 237, 200)
+	for i, h := range headers {
+		pdf.CellFormat(widths[i], 8, h, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	var totalWeight float64
+	var totalPoints int
+	weightByType := map[string]float64{}
+	var wasteTypes []string
+	for i, d := range deposits {
+		weight := 0.0
+		if d.Weight != nil {
+			weight = *d.Weight
+		}
+		points := pointsByDeposit[d.ID]
+		totalWeight += weight
+		totalPoints += points
+		if _, ok := weightByType[d.WasteType]; !ok {
+			wasteTypes = append(wasteTypes, d.WasteType)
+		}
+		weightByType[d.WasteType] += weight
+
+		pdf.CellFormat(widths[0], 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[1], 7, d.PickupDate.In(jakartaLoc).Format("02/01/2006"), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[2], 7, tr(d.WasteType), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 7, fmt.Sprintf("%d", d.BinCount), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[4], 7, fmt.Sprintf("%.1f", weight), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[5], 7, fmt.Sprintf("%d", points), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	if len(deposits) == 0 {
+		pdf.CellFormat(163, 7, "Tidak ada penyetoran selesai pada periode ini", "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	// Summary
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Ringkasan", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, wt := range wasteTypes {
+		pdf.CellFormat(80, 7, tr(wt), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%.1f Kg", weightByType[wt]), "1", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(80, 7, "Total berat", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 7, fmt.Sprintf("%.1f Kg", totalWeight), "1", 1, "R", false, 0, "")
+	pdf.CellFormat(80, 7, "Jumlah penjemputan", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 7, fmt.Sprintf("%d", len(deposits)), "1", 1, "R", false, 0, "")
+	pdf.CellFormat(80, 7, "Poin diperoleh", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 7, fmt.Sprintf("%d", totalPoints), "1", 1, "R", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// monthlyReport returns the stored report for a finished month, building and storing it if missing.
+// The current month is always rendered fresh and never stored.
+func monthlyReport(ctx context.Context, school models.School, month time.Time) ([]byte, error) {
+	now := time.Now().In(jakartaLoc)
+	finished := month.AddDate(0, 1, 0).Before(now)
+	key := monthlyReportKey(school, month)
+
+	if finished {
+		if file, err := storage.Files.Get(ctx, key); err == nil {
+			defer file.Close()
+			return io.ReadAll(file)
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to read stored report %s: %v", key, err)
+		}
+	}
+
+	data, err := buildMonthlyReport(school, month)
+	if err != nil {
+		return nil, err
+	}
+
+	if finished {
+		if err := storage.Files.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+			log.Printf("Failed to store report %s: %v", key, err)
+		}
+	}
+	return data, nil
+}
+
+// monthlyReportHeader is the school data printed on a report; stored reports are stale once it changes
+func monthlyReportHeader(school models.School) string {
+	npsn := ""
+	if school.NPSN != nil {
+		npsn = *school.NPSN
+	}
+	return school.Name + "\n" + npsn + "\n" + school.Address
+}
+
+// invalidateMonthlyReport drops the stored report covering a deposit's pickup month
+// so the next request rebuilds it with the deposit's current data
+func invalidateMonthlyReport(deposit models.WasteDeposit) {
+	if deposit.SchoolID == nil {
+		return
+	}
+	pickup := deposit.PickupDate.In(jakartaLoc)
+	month := time.Date(pickup.Year(), pickup.Month(), 1, 0, 0, 0, 0, jakartaLoc)
+	key := monthlyReportKey(models.School{ID: *deposit.SchoolID}, month)
+	if err := storage.Files.Delete(context.Background(), key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to delete stored report %s: %v", key, err)
+	}
+}
+
+// invalidateSchoolReports drops every stored report of a school, e.g. after a merge or a rename
+func invalidateSchoolReports(schoolID uuid.UUID) {
+	ctx := context.Background()
+	objects, err := storage.Files.List(ctx, fmt.Sprintf("reports/%s/", schoolID))
+	if err != nil {
+		log.Printf("Failed to list stored reports of school %s: %v", schoolID, err)
+		return
+	}
+	for _, obj := range objects {
+		if err := storage.Files.Delete(ctx, obj.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to delete stored report %s: %v", obj.Key, err)
+		}
+	}
+}
+
+func sendMonthlyReport(c *gin.Context, school models.School, month time.Time) {
+	data, err := monthlyReport(c.Request.Context(), school, month)
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to generate report").Wrap(err))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+monthlyReportFilename(school, month)+`"`)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GetMyMonthlyReport returns the PDF report of the authenticated user's school for ?month=YYYY-MM (default last month)
+func GetMyMonthlyReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	month, ok := parseReportMonth(c)
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("User not found"))
+		return
+	}
+	if user.SchoolID == nil {
+		apperr.Abort(c, apperr.BadRequest("Set your school in your profile first").WithCode(apperr.CodeSchoolRequired))
+		return
+	}
+
+	var school models.School
+	if err := config.DB.Where("id = ?", *user.SchoolID).First(&school).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+
+	sendMonthlyReport(c, school, month)
+}
+
+// GetSchoolMonthlyReport returns the PDF report for ?school_id= and ?month=YYYY-MM (admin only)
+func GetSchoolMonthlyReport(c *gin.Context) {
+	schoolID := c.Query("school_id")
+	if schoolID == "" {
+		apperr.Abort(c, apperr.BadRequest("school_id is required"))
+		return
+	}
+
+	var school models.School
+	if err := config.DB.Where("id = ?", schoolID).First(&school).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+
+	month, ok := parseReportMonth(c)
+	if !ok {
+		return
+	}
+
+	sendMonthlyReport(c, school, month)
+}
+
+// StartMonthlyReportJob pre-builds last month's report for every school with completed deposits.
+// Reports already in storage are skipped, so running it repeatedly is cheap.
+func StartMonthlyReportJob(interval time.Duration) {
+	go func() {
+		// Run once at startup, then on every tick
+		buildLastMonthReports()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			buildLastMonthReports()
+		}
+	}()
+}
+
+func buildLastMonthReports() {
+	now := time.Now().In(jakartaLoc)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, jakartaLoc).AddDate(0, -1, 0)
+
+	active := config.DB.Model(&models.WasteDeposit{}).
+		Select("school_id").
+		Where("status = ? AND pickup_date >= ? AND pickup_date < ?", "completed", month, month.AddDate(0, 1, 0))
+
+	var schools []models.School
+	if err := config.DB.Where("id IN (?)", active).Find(&schools).Error; err != nil {
+		log.Println("Monthly reports:", err)
+		return
+	}
+
+	ctx := context.Background()
+	for _, school := range schools {
+		if file, err := storage.Files.Get(ctx, monthlyReportKey(school, month)); err == nil {
+			file.Close()
+			continue
+		}
+		if _, err := monthlyReport(ctx, school, month); err != nil {
+			log.Printf("Monthly reports: %s: %v", school.Name, err)
+		}
+	}
+}
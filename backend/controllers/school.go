@@ -0,0 +1,870 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var schoolRoles = []string{"member", "coordinator"}
+
+var errSchoolNotFound = errors.New("school not found")
+
+type SchoolInput struct {
+	Name         string   `json:"name" binding:"required"`
+	NPSN         string   `json:"npsn"`
+	Address      string   `json:"address"`
+	ContactName  string   `json:"contact_name"`
+	ContactPhone string   `json:"contact_phone"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+}
+
+// normalizeSchoolName trims and collapses whitespace so "SMAN  1 " and "SMAN 1" match
+func normalizeSchoolName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// findOrCreateSchool returns the school whose name matches case-insensitively, creating it if none does.
+// Only used to migrate legacy free-text names; requests never create schools.
+func findOrCreateSchool(tx *gorm.DB, name string) (*models.School, error) {
+	name = normalizeSchoolName(name)
+
+	var school models.School
+	err := tx.Where("LOWER(name) = LOWER(?)", name).Order("created_at").First(&school).Error
+	if err == nil {
+		return &school, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	school = models.School{Name: name}
+	if err := tx.Create(&school).Error; err != nil {
+		return nil, err
+	}
+	return &school, nil
+}
+
+// resolveSchool finds the existing school referenced by a request: an explicit school_id wins,
+// otherwise school_name is matched case-insensitively. Schools are only created by admins.
+// Returns nil when both are empty and errSchoolNotFound when nothing matches.
+func resolveSchool(schoolID, schoolName string) (*models.School, error) {
+	query := config.DB
+	if schoolID != "" {
+		id, err := uuid.Parse(schoolID)
+		if err != nil {
+			return nil, errSchoolNotFound
+		}
+		query = query.Where("id = ?", id)
+	} else if name := normalizeSchoolName(schoolName); name != "" {
+		query = query.Where("LOWER(name) = LOWER(?)", name).Order("created_at")
+	} else {
+		return nil, nil
+	}
+
+	var school models.School
+	if err := query.First(&school).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errSchoolNotFound
+		}
+		return nil, err
+	}
+	return &school, nil
+}
+
+// requestSchool points the user at a school. Admins join directly; everyone else waits in
+// pending_school_id until a coordinator of the school or an admin approves.
+func requestSchool(user *models.User, school *models.School) {
+	user.RequestedSchool = ""
+	if user.SchoolID != nil && *user.SchoolID == school.ID {
+		user.PendingSchoolID = nil
+		return
+	}
+	if user.Role == "admin" {
+		user.SchoolID = &school.ID
+		user.SchoolName = school.Name
+		user.SchoolRole = "member"
+		user.PendingSchoolID = nil
+		return
+	}
+	user.PendingSchoolID = &school.ID
+}
+
+// requestNewSchool records a school name that matches no registered school. An admin adds the school
+// (or points the name at an existing one) from the school requests queue, then the user joins it.
+func requestNewSchool(user *models.User, name string) {
+	user.PendingSchoolID = nil
+	user.RequestedSchool = normalizeSchoolName(name)
+}
+
+// setUserSchool applies the school fields of a profile or registration request: an existing school is
+// asked to join, an unknown name becomes a request for a new school. Only an unknown school_id is an error.
+func setUserSchool(user *models.User, schoolID, schoolName string) error {
+	school, err := resolveSchool(schoolID, schoolName)
+	switch {
+	case errors.Is(err, errSchoolNotFound) && schoolID == "":
+		requestNewSchool(user, schoolName)
+		return nil
+	case err != nil:
+		return err
+	case school != nil:
+		requestSchool(user, school)
+	}
+	return nil
+}
+
+// pendingSchoolName is the name of the school the user is waiting to join, empty when there is none
+func pendingSchoolName(user models.User) string {
+	if user.PendingSchoolID != nil {
+		var school models.School
+		if err := config.DB.Select("name").Where("id = ?", *user.PendingSchoolID).First(&school).Error; err == nil {
+			return school.Name
+		}
+	}
+	return user.RequestedSchool
+}
+
+// MigrateSchools links users, deposits and school announcements still identified by free-text school names
+// to School rows. Names differing only in case or spacing share one school; other duplicates are merged by an admin.
+func MigrateSchools() {
+	var names []string
+	config.DB.Raw(`SELECT DISTINCT school_name FROM users WHERE school_id IS NULL AND school_name <> ''
+		UNION SELECT DISTINCT school_name FROM waste_deposits WHERE school_id IS NULL AND school_name <> ''`).
+		Scan(&names)
+
+	for _, name := range names {
+		school, err := findOrCreateSchool(config.DB, name)
+		if err != nil {
+			log.Printf("School migration: %q: %v", name, err)
+			continue
+		}
+		updates := map[string]interface{}{"school_id": school.ID, "school_name": school.Name}
+		config.DB.Model(&models.User{}).Where("school_id IS NULL AND school_name = ?", name).Updates(updates)
+		config.DB.Model(&models.WasteDeposit{}).Where("school_id IS NULL AND school_name = ?", name).Updates(updates)
+		assignFirstCoordinator(config.DB, school.ID)
+	}
+
+	// Announcements used to target schools by name
+	var announcements []models.Announcement
+	config.DB.Where("target_type = ? AND status = ?", "schools", "scheduled").Find(&announcements)
+	for _, a := range announcements {
+		changed := false
+		for i, target := range a.TargetSchools {
+			if _, err := uuid.Parse(target); err == nil {
+				continue
+			}
+			school, err := findOrCreateSchool(config.DB, target)
+			if err != nil {
+				continue
+			}
+			a.TargetSchools[i] = school.ID.String()
+			changed = true
+		}
+		if changed {
+			config.DB.Model(&a).Update("target_schools", a.TargetSchools)
+		}
+	}
+
+	if len(names) > 0 {
+		log.Printf("School migration: linked %d school names", len(names))
+	}
+}
+
+// assignFirstCoordinator makes the earliest non-admin member of a school without a coordinator its
+// coordinator, so join requests to the school have someone to approve them
+func assignFirstCoordinator(tx *gorm.DB, schoolID uuid.UUID) error {
+	var coordinators int64
+	if err := tx.Model(&models.User{}).Where("school_id = ? AND school_role = ?", schoolID, "coordinator").Count(&coordinators).Error; err != nil {
+		return err
+	}
+	if coordinators > 0 {
+		return nil
+	}
+
+	var first models.User
+	err := tx.Where("school_id = ? AND role <> ?", schoolID, "admin").Order("created_at").First(&first).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", first.ID).Update("school_role", "coordinator").Error
+}
+
+// canManageSchool reports whether the user is an admin or a coordinator of the school
+func canManageSchool(userID uuid.UUID, schoolID uuid.UUID) bool {
+	var user models.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return false
+	}
+	if user.Role == "admin" {
+		return true
+	}
+	return isCoordinatorOf(user, schoolID)
+}
+
+// isSchoolCoordinator reports whether the user is a coordinator of the school, without the admin shortcut
+func isSchoolCoordinator(userID uuid.UUID, schoolID uuid.UUID) bool {
+	var user models.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return false
+	}
+	return isCoordinatorOf(user, schoolID)
+}
+
+func isCoordinatorOf(user models.User, schoolID uuid.UUID) bool {
+	return user.SchoolID != nil && *user.SchoolID == schoolID && user.SchoolRole == "coordinator"
+}
+
+func applySchoolInput(school *models.School, input SchoolInput) {
+	school.Name = normalizeSchoolName(input.Name)
+	school.Address = input.Address
+	school.ContactName = input.ContactName
+	school.ContactPhone = input.ContactPhone
+	school.Latitude = input.Latitude
+	school.Longitude = input.Longitude
+	school.NPSN = nil
+	if npsn := strings.TrimSpace(input.NPSN); npsn != "" {
+		school.NPSN = &npsn
+	}
+}
+
+// GetSchools lists schools, optionally filtered by ?q= on name or NPSN, with ?page= and ?limit=
+func GetSchools(c *gin.Context) {
+	page, limit, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.School{})
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("name ILIKE ? OR npsn = ?", "%"+q+"%", q)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch schools").Wrap(err))
+		return
+	}
+
+	var schools []models.School
+	if err := query.Order("name").Offset((page - 1) * limit).Limit(limit).Find(&schools).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch schools").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schools": schools,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
+
+// GetSchoolByID returns a school with its member count
+func GetSchoolByID(c *gin.Context) {
+	var school models.School
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&school).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+
+	var members int64
+	config.DB.Model(&models.User{}).Where("school_id = ?", school.ID).Count(&members)
+
+	c.JSON(http.StatusOK, gin.H{
+		"school":       school,
+		"member_count": members,
+	})
+}
+
+// GetSchoolMembers lists the users of a school (admins and members of that school)
+func GetSchoolMembers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var school models.School
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&school).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err != nil {
+		apperr.Abort(c, apperr.Unauthorized("User not found"))
+		return
+	}
+	if user.Role != "admin" && (user.SchoolID == nil || *user.SchoolID != school.ID) {
+		apperr.Abort(c, apperr.Forbidden("You are not a member of this school"))
+		return
+	}
+
+	var members []models.User
+	if err := config.DB.Where("school_id = ?", school.ID).Order("school_role, name").Find(&members).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch members").Wrap(err))
+		return
+	}
+
+	result := make([]gin.H, len(members))
+	for i, m := range members {
+		result[i] = gin.H{
+			"id":          m.ID,
+			"name":        m.Name,
+			"picture":     m.Picture,
+			"school_role": m.SchoolRole,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": result})
+}
+
+// CreateSchool registers a school (admin only)
+func CreateSchool(c *gin.Context) {
+	var input SchoolInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	var school models.School
+	applySchoolInput(&school, input)
+	if school.Name == "" {
+		apperr.Abort(c, apperr.BadRequest("name is required"))
+		return
+	}
+
+	if err := config.DB.Create(&school).Error; err != nil {
+		apperr.Abort(c, apperr.Conflict("Failed to create school, NPSN may already be registered"))
+		return
+	}
+	recordAudit(c, "school.create", "school", school.ID.String(), nil, schoolAuditFields(school))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "School created successfully",
+		"school":  school,
+	})
+}
+
+// UpdateSchool edits a school's details (admin or school coordinator).
+// A rename is copied to the school_name of its users and deposits.
+func UpdateSchool(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var school models.School
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&school).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+
+	if !canManageSchool(userID.(uuid.UUID), school.ID) {
+		apperr.Abort(c, apperr.Forbidden("Only admins and school coordinators can edit this school"))
+		return
+	}
+
+	var input SchoolInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	oldName := school.Name
+	oldHeader := monthlyReportHeader(school)
+	auditBefore := schoolAuditFields(school)
+	applySchoolInput(&school, input)
+	if school.Name == "" {
+		apperr.Abort(c, apperr.BadRequest("name is required"))
+		return
+	}
+
+	tx := config.DB.Begin()
+	if err := tx.Save(&school).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Conflict("Failed to update school, NPSN may already be registered"))
+		return
+	}
+	if school.Name != oldName {
+		if err := tx.Model(&models.User{}).Where("school_id = ?", school.ID).Update("school_name", school.Name).Error; err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.Internal("Failed to update school"))
+			return
+		}
+		if err := tx.Model(&models.WasteDeposit{}).Where("school_id = ?", school.ID).Update("school_name", school.Name).Error; err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.Internal("Failed to update school"))
+			return
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update school").Wrap(err))
+		return
+	}
+	recordAudit(c, "school.update", "school", school.ID.String(), auditBefore, schoolAuditFields(school))
+
+	if school.Name != oldName {
+		go refreshLeaderboards()
+	}
+	if monthlyReportHeader(school) != oldHeader {
+		invalidateSchoolReports(school.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "School updated successfully",
+		"school":  school,
+	})
+}
+
+// UpdateSchoolMember changes a member's role within the school (admin or school coordinator)
+func UpdateSchoolMember(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid school id"))
+		return
+	}
+
+	if !canManageSchool(userID.(uuid.UUID), schoolID) {
+		apperr.Abort(c, apperr.Forbidden("Only admins and school coordinators can change member roles"))
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	if !containsString(schoolRoles, input.Role) {
+		apperr.Abort(c, apperr.BadRequest("Invalid role. Must be: member or coordinator"))
+		return
+	}
+
+	var member models.User
+	if err := config.DB.Where("id = ? AND school_id = ?", c.Param("user_id"), schoolID).First(&member).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Member not found"))
+		return
+	}
+
+	oldRole := member.SchoolRole
+	if err := config.DB.Model(&member).Update("school_role", input.Role).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update member").Wrap(err))
+		return
+	}
+	recordAudit(c, "user.school_role", "user", member.ID.String(),
+		map[string]interface{}{"school_id": schoolID.String(), "school_role": oldRole},
+		map[string]interface{}{"school_id": schoolID.String(), "school_role": input.Role})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated successfully"})
+}
+
+// GetSchoolJoinRequests lists the users waiting to join the school (admin or school coordinator)
+func GetSchoolJoinRequests(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		apperr.Abort(c, apperr.BadRequest("Invalid school id"))
+		return
+	}
+	if !canManageSchool(userID.(uuid.UUID), schoolID) {
+		apperr.Abort(c, apperr.Forbidden("Only admins and school coordinators can review join requests"))
+		return
+	}
+
+	var users []models.User
+	if err := config.DB.Where("pending_school_id = ?", schoolID).Order("updated_at").Find(&users).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch join requests").Wrap(err))
+		return
+	}
+
+	result := make([]gin.H, len(users))
+	for i, u := range users {
+		result[i] = gin.H{
+			"id":          u.ID,
+			"name":        u.Name,
+			"email":       u.Email,
+			"picture":     u.Picture,
+			"school_name": u.SchoolName, // Current school, if any
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": result})
+}
+
+// ApproveSchoolJoinRequest moves a user into the school as a member (admin or school coordinator)
+func ApproveSchoolJoinRequest(c *gin.Context) {
+	reviewSchoolJoinRequest(c, true)
+}
+
+// RejectSchoolJoinRequest clears a user's request to join the school (admin or school coordinator)
+func RejectSchoolJoinRequest(c *gin.Context) {
+	reviewSchoolJoinRequest(c, false)
+}
+
+func reviewSchoolJoinRequest(c *gin.Context, approve bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var school models.School
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&school).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+	if !canManageSchool(userID.(uuid.UUID), school.ID) {
+		apperr.Abort(c, apperr.Forbidden("Only admins and school coordinators can review join requests"))
+		return
+	}
+
+	var member models.User
+	if err := config.DB.Where("id = ? AND pending_school_id = ?", c.Param("user_id"), school.ID).First(&member).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Join request not found"))
+		return
+	}
+
+	auditBefore := map[string]interface{}{"school_id": nil, "pending_school_id": school.ID.String()}
+	if member.SchoolID != nil {
+		auditBefore["school_id"] = member.SchoolID.String()
+	}
+	updates := map[string]interface{}{"pending_school_id": nil}
+	action := "school.join_reject"
+	if approve {
+		updates["school_id"] = school.ID
+		updates["school_name"] = school.Name
+		updates["school_role"] = "member"
+		action = "school.join_approve"
+	}
+
+	// The condition keeps a request that changed in the meantime from being applied
+	result := config.DB.Model(&models.User{}).Where("id = ? AND pending_school_id = ?", member.ID, school.ID).Updates(updates)
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to update member").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperr.Abort(c, apperr.NotFound("Join request not found"))
+		return
+	}
+
+	auditAfter := map[string]interface{}{"school_id": auditBefore["school_id"], "pending_school_id": nil}
+	if approve {
+		auditAfter["school_id"] = school.ID.String()
+	}
+	recordAudit(c, action, "user", member.ID.String(), auditBefore, auditAfter)
+
+	message := "Join request rejected"
+	if approve {
+		message = "Join request approved"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// GetSchoolRequests lists the names of schools users asked for that aren't registered yet (admin only)
+func GetSchoolRequests(c *gin.Context) {
+	var users []models.User
+	if err := config.DB.Where("requested_school <> ''").Order("created_at").Find(&users).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch school requests").Wrap(err))
+		return
+	}
+
+	// Spellings that differ only in case are the same request
+	var result []gin.H
+	index := map[string]int{}
+	for _, u := range users {
+		key := strings.ToLower(u.RequestedSchool)
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, gin.H{"name": u.RequestedSchool, "users": []gin.H{}})
+		}
+		result[i]["users"] = append(result[i]["users"].([]gin.H), gin.H{
+			"id":      u.ID,
+			"name":    u.Name,
+			"email":   u.Email,
+			"picture": u.Picture,
+		})
+	}
+	if result == nil {
+		result = []gin.H{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": result})
+}
+
+// ApproveSchoolRequest adds the requested school, or points the name at an existing school_id, and moves
+// every user who asked for it into the school. The first of them becomes coordinator if the school has none (admin only)
+func ApproveSchoolRequest(c *gin.Context) {
+	var input struct {
+		Name     string `json:"name" binding:"required"`
+		SchoolID string `json:"school_id"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	name := normalizeSchoolName(input.Name)
+
+	tx := config.DB.Begin()
+	var school *models.School
+	if input.SchoolID != "" {
+		var existing models.School
+		if err := tx.Where("id = ?", input.SchoolID).First(&existing).Error; err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.NotFound("School not found"))
+			return
+		}
+		school = &existing
+	} else {
+		found, err := findOrCreateSchool(tx, name)
+		if err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.Internal("Failed to create school").Wrap(err))
+			return
+		}
+		school = found
+	}
+
+	result := tx.Model(&models.User{}).Where("LOWER(requested_school) = LOWER(?)", name).Updates(map[string]interface{}{
+		"requested_school": "",
+		"school_id":        school.ID,
+		"school_name":      school.Name,
+		"school_role":      "member",
+	})
+	if result.Error != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to update members").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		apperr.Abort(c, apperr.NotFound("School request not found"))
+		return
+	}
+	if err := assignFirstCoordinator(tx, school.ID); err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to assign a coordinator").Wrap(err))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to approve school request").Wrap(err))
+		return
+	}
+
+	recordAudit(c, "school.request_approve", "school", school.ID.String(),
+		map[string]interface{}{"requested_school": name},
+		map[string]interface{}{"school_id": school.ID.String(), "members": result.RowsAffected})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "School request approved",
+		"school":  school,
+		"members": result.RowsAffected,
+	})
+}
+
+// RejectSchoolRequest clears a requested school name from every user who asked for it (admin only)
+func RejectSchoolRequest(c *gin.Context) {
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	name := normalizeSchoolName(input.Name)
+
+	result := config.DB.Model(&models.User{}).Where("LOWER(requested_school) = LOWER(?)", name).Update("requested_school", "")
+	if result.Error != nil {
+		apperr.Abort(c, apperr.Internal("Failed to reject school request").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperr.Abort(c, apperr.NotFound("School request not found"))
+		return
+	}
+	recordAudit(c, "school.request_reject", "school_request", name,
+		map[string]interface{}{"requested_school": name, "users": result.RowsAffected}, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "School request rejected"})
+}
+
+// MergeSchools folds duplicate schools into the school in the URL (admin only).
+// Members, join requests, deposits, saved pickup addresses, recurring pickups and scheduled announcements
+// move to the target and the duplicates are deleted.
+func MergeSchools(c *gin.Context) {
+	var target models.School
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&target).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("School not found"))
+		return
+	}
+
+	var input struct {
+		SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+
+	var sources []models.School
+	config.DB.Where("id IN ? AND id <> ?", input.SourceIDs, target.ID).Find(&sources)
+	if len(sources) == 0 {
+		apperr.Abort(c, apperr.BadRequest("No schools to merge"))
+		return
+	}
+	sourceIDs := make([]uuid.UUID, len(sources))
+	merged := make(map[string]bool, len(sources))
+	for i, s := range sources {
+		sourceIDs[i] = s.ID
+		merged[s.ID.String()] = true
+	}
+
+	tx := config.DB.Begin()
+	updates := map[string]interface{}{"school_id": target.ID, "school_name": target.Name}
+	users := tx.Model(&models.User{}).Where("school_id IN ?", sourceIDs).Updates(updates)
+	if users.Error != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+	if err := tx.Model(&models.User{}).Where("pending_school_id IN ?", sourceIDs).Update("pending_school_id", target.ID).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+	// Saved addresses move too; the target keeps its own default when it has one
+	var targetDefaults int64
+	tx.Model(&models.PickupAddress{}).Where("school_id = ? AND is_default = ?", target.ID, true).Count(&targetDefaults)
+	addressUpdates := map[string]interface{}{"school_id": target.ID}
+	if targetDefaults > 0 {
+		addressUpdates["is_default"] = false
+	}
+	if err := tx.Model(&models.PickupAddress{}).Where("school_id IN ?", sourceIDs).Updates(addressUpdates).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+	if targetDefaults == 0 {
+		// Several duplicates may each bring a default; keep the oldest
+		var defaults []models.PickupAddress
+		tx.Where("school_id = ? AND is_default = ?", target.ID, true).Order("created_at").Find(&defaults)
+		for i := 1; i < len(defaults); i++ {
+			if err := tx.Model(&defaults[i]).Update("is_default", false).Error; err != nil {
+				tx.Rollback()
+				apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+				return
+			}
+		}
+	}
+	if err := tx.Model(&models.RecurringPickup{}).Where("school_id IN ?", sourceIDs).Update("school_id", target.ID).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+	deposits := tx.Model(&models.WasteDeposit{}).Where("school_id IN ?", sourceIDs).Updates(updates)
+	if deposits.Error != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+
+	var announcements []models.Announcement
+	tx.Where("target_type = ? AND status = ?", "schools", "scheduled").Find(&announcements)
+	for _, a := range announcements {
+		targets := make([]string, 0, len(a.TargetSchools))
+		changed := false
+		for _, id := range a.TargetSchools {
+			if merged[id] {
+				id = target.ID.String()
+				changed = true
+			}
+			if !containsString(targets, id) {
+				targets = append(targets, id)
+			}
+		}
+		if changed {
+			if err := tx.Model(&a).Update("target_schools", targets).Error; err != nil {
+				tx.Rollback()
+				apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+				return
+			}
+		}
+	}
+
+	// Keep the NPSN of a duplicate when the target has none
+	if target.NPSN == nil {
+		for _, s := range sources {
+			if s.NPSN != nil {
+				target.NPSN = s.NPSN
+				break
+			}
+		}
+	}
+	if err := tx.Where("id IN ?", sourceIDs).Delete(&models.School{}).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+	if err := tx.Save(&target).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to merge schools"))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to merge schools").Wrap(err))
+		return
+	}
+	mergedIDs := make([]string, len(sources))
+	for i, s := range sources {
+		mergedIDs[i] = s.ID.String()
+	}
+	recordAudit(c, "school.merge", "school", target.ID.String(), nil, map[string]interface{}{
+		"merged_school_ids": mergedIDs,
+		"users_moved":       users.RowsAffected,
+		"deposits_moved":    deposits.RowsAffected,
+	})
+
+	go refreshLeaderboards()
+
+	// Stored reports of both sides are out of date now that the deposits moved
+	for _, s := range sources {
+		invalidateSchoolReports(s.ID)
+	}
+	invalidateSchoolReports(target.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Schools merged successfully",
+		"school":         target,
+		"merged":         len(sources),
+		"users_moved":    users.RowsAffected,
+		"deposits_moved": deposits.RowsAffected,
+	})
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// parseDateRange reads ?from= and ?to= (YYYY-MM-DD, Jakarta time, to inclusive).
+// Defaults to the defaultDays days up to today. Writes a 400 and returns ok=false on invalid input.
+func parseDateRange(c *gin.Context, defaultDays int) (from, to time.Time, ok bool) {
+	now := time.Now().In(jakartaLoc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jakartaLoc)
+	to = today.AddDate(0, 0, 1)
+	from = to.AddDate(0, 0, -defaultDays)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid from date. Use YYYY-MM-DD"))
+			return from, to, false
+		}
+		from = parsed
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid to date. Use YYYY-MM-DD"))
+			return from, to, false
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		apperr.Abort(c, apperr.BadRequest("from must not be after to"))
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// GetAdminStats returns deposit statistics for the admin dashboard (admin only).
+// Supports ?from=, ?to= (YYYY-MM-DD, default last 30 days) and ?interval=day|week|month for the time series.
+func GetAdminStats(c *gin.Context) {
+	from, to, ok := parseDateRange(c, 30)
+	if !ok {
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" && interval != "month" {
+		apperr.Abort(c, apperr.BadRequest("Invalid interval. Must be: day, week, or month"))
+		return
+	}
+
+	base := func() *gorm.DB {
+		return config.DB.Model(&models.WasteDeposit{}).Where("created_at >= ? AND created_at < ?", from, to)
+	}
+
+	type statusCount struct {
+		Status string `json:"status"`
+		Count  int64  `json:"count"`
+	}
+	var byStatus []statusCount
+	if err := base().Select("status, COUNT(*) AS count").Group("status").Scan(&byStatus).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to compute statistics").Wrap(err))
+		return
+	}
+
+	countsByStatus := map[string]int64{"pending": 0, "proses": 0, "completed": 0, "rejected": 0}
+	var totalDeposits int64
+	for _, s := range byStatus {
+		countsByStatus[s.Status] = s.Count
+		totalDeposits += s.Count
+	}
+
+	var totals struct {
+		TotalWeight   float64
+		ActiveSchools int64
+	}
+	if err := base().
+		Select("COALESCE(SUM(weight) FILTER (WHERE status = 'completed'), 0) AS total_weight, COUNT(DISTINCT school_id) AS active_schools").
+		Scan(&totals).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to compute statistics").Wrap(err))
+		return
+	}
+
+	type wasteTypeWeight struct {
+		WasteType string  `json:"waste_type"`
+		Weight    float64 `json:"weight"`
+		Count     int64   `json:"count"`
+	}
+	weightByType := []wasteTypeWeight{}
+	if err := base().
+		Select("waste_type, COALESCE(SUM(weight), 0) AS weight, COUNT(*) AS count").
+		Where("status = ?", "completed").
+		Group("waste_type").
+		Order("weight DESC").
+		Scan(&weightByType).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to compute statistics").Wrap(err))
+		return
+	}
+
+	type seriesPoint struct {
+		Period    string  `json:"period"`
+		Deposits  int64   `json:"deposits"`
+		Completed int64   `json:"completed"`
+		Weight    float64 `json:"weight"`
+	}
+	series := []seriesPoint{}
+	// Bucket by local (WIB) calendar so a day runs from midnight Jakarta time
+	if err := base().
+		Select("to_char(date_trunc(?, created_at AT TIME ZONE 'Asia/Jakarta'), 'YYYY-MM-DD') AS period, "+
+			"COUNT(*) AS deposits, "+
+			"COUNT(*) FILTER (WHERE status = 'completed') AS completed, "+
+			"COALESCE(SUM(weight) FILTER (WHERE status = 'completed'), 0) AS weight", interval).
+		Group("period").
+		Order("period").
+		Scan(&series).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to compute statistics").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":                 from.Format("2006-01-02"),
+		"to":                   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"total_deposits":       totalDeposits,
+		"counts_by_status":     countsByStatus,
+		"total_weight":         totals.TotalWeight,
+		"weight_by_waste_type": weightByType,
+		"active_schools":       totals.ActiveSchools,
+		"interval":             interval,
+		"time_series":          series,
+	})
+}
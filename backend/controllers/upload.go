@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/storage"
+	"backend-api/utils"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Thumbnail sizes generated for deposit photos and profile pictures, longest side in pixels
+var thumbnailSizes = map[string]int{
+	"small":  150,
+	"medium": 480,
+}
+
+// maxUploadSize returns the largest accepted upload in bytes, from UPLOAD_MAX_SIZE_MB (default 5)
+func maxUploadSize() int64 {
+	mb, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_SIZE_MB"))
+	if err != nil || mb <= 0 {
+		mb = 5
+	}
+	return int64(mb) << 20
+}
+
+// saveUploadedImage validates and re-encodes an uploaded image, stores it as dir/name and returns its URL.
+// When withThumbnails is set, thumbnails are stored next to it and their URLs returned by size name.
+func saveUploadedImage(c *gin.Context, file *multipart.FileHeader, dir, name string, withThumbnails bool) (string, map[string]string, error) {
+	maxSize := maxUploadSize()
+	if file.Size > maxSize {
+		return "", nil, utils.ErrFileTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", nil, err
+	}
+	defer src.Close()
+
+	processed, err := utils.ProcessImage(src, maxSize)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ctx := c.Request.Context()
+	key := dir + "/" + name + processed.Ext
+	if err := storage.Files.Put(ctx, key, bytes.NewReader(processed.Data), int64(len(processed.Data)), processed.ContentType); err != nil {
+		return "", nil, err
+	}
+
+	if !withThumbnails {
+		return storage.Files.URL(key), nil, nil
+	}
+
+	thumbnails := make(map[string]string, len(thumbnailSizes))
+	for sizeName, size := range thumbnailSizes {
+		data, err := utils.Thumbnail(processed.Image, size)
+		if err == nil {
+			thumbKey := dir + "/" + name + "_" + sizeName + ".jpg"
+			err = storage.Files.Put(ctx, thumbKey, bytes.NewReader(data), int64(len(data)), "image/jpeg")
+			thumbnails[sizeName] = storage.Files.URL(thumbKey)
+		}
+		if err != nil {
+			// Don't leave a partial set of files behind
+			deleteStoredFiles(storage.Files.URL(key), thumbnails)
+			return "", nil, err
+		}
+	}
+
+	return storage.Files.URL(key), thumbnails, nil
+}
+
+// deleteStoredFiles removes a stored file and its thumbnails. Failures are only logged,
+// anything left behind is picked up by the upload sweeper.
+func deleteStoredFiles(fileURL string, thumbnails map[string]string) {
+	urls := []string{fileURL}
+	for _, u := range thumbnails {
+		urls = append(urls, u)
+	}
+
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		key, ok := storage.Files.Key(u)
+		if !ok {
+			continue
+		}
+		if err := storage.Files.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete %s: %v", key, err)
+		}
+	}
+}
+
+// respondUploadError maps image validation errors to client errors and anything else to a 500 with message
+func respondUploadError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrFileTooLarge):
+		apperr.Abort(c, apperr.New(http.StatusRequestEntityTooLarge, apperr.CodeFileTooLarge, "File too large. Maximum size is {size} MB").
+			WithParams(apperr.Params{"size": strconv.FormatInt(maxUploadSize()>>20, 10)}))
+	case errors.Is(err, utils.ErrImageTooLarge):
+		apperr.Abort(c, apperr.New(http.StatusRequestEntityTooLarge, apperr.CodeFileTooLarge, "Image dimensions are too large. Maximum is 40 megapixels"))
+	case errors.Is(err, utils.ErrUnsupportedImage):
+		apperr.Abort(c, apperr.New(http.StatusBadRequest, apperr.CodeUnsupportedFile, "Unsupported file type. Allowed: JPEG, PNG, WebP"))
+	default:
+		apperr.Abort(c, apperr.Internal(message).Wrap(err))
+	}
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"backend-api/storage"
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Files younger than this are never swept, so uploads whose database row is still being written survive
+const uploadSweepGracePeriod = 24 * time.Hour
+
+// Storage prefixes holding user uploads. Other prefixes (such as generated reports) are never swept.
+var uploadPrefixes = []string{"profiles/", "deposits/", "chat/", "disputes/"}
+
+// UploadSweepReport lists what a sweep removed, or would remove in dry-run mode
+type UploadSweepReport struct {
+	DryRun     bool     `json:"dry_run"`
+	Scanned    int      `json:"scanned"`
+	Referenced int      `json:"referenced"`
+	Orphaned   []string `json:"orphaned"`
+	FreedBytes int64    `json:"freed_bytes"`
+	Failed     []string `json:"failed,omitempty"`
+}
+
+// referencedUploadKeys collects the storage keys of every file still used by a user, deposit, chat message or dispute
+func referencedUploadKeys() (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	add := func(u string) {
+		if key, ok := storage.Files.Key(u); ok {
+			referenced[key] = true
+		}
+	}
+
+	var users []models.User
+	if err := config.DB.Select("picture", "picture_thumbnails").Where("picture <> ''").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		add(u.Picture)
+		for _, t := range u.PictureThumbnails {
+			add(t)
+		}
+	}
+
+	var deposits []models.WasteDeposit
+	if err := config.DB.Select("photo_proof", "photo_thumbnails").Where("photo_proof <> ''").Find(&deposits).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range deposits {
+		add(d.PhotoProof)
+		for _, t := range d.PhotoThumbnails {
+			add(t)
+		}
+	}
+
+	var attachments []string
+	if err := config.DB.Model(&models.ChatMessage{}).Where("attachment <> ''").Pluck("attachment", &attachments).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		add(a)
+	}
+
+	var disputes []models.DepositDispute
+	if err := config.DB.Select("photos").Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range disputes {
+		for _, p := range d.Photos {
+			add(p)
+		}
+	}
+
+	return referenced, nil
+}
+
+// SweepOrphanedUploads deletes stored files that nothing references anymore.
+// With dryRun set nothing is deleted and the report lists what would be.
+func SweepOrphanedUploads(ctx context.Context, dryRun bool) (*UploadSweepReport, error) {
+	var objects []storage.Object
+	for _, prefix := range uploadPrefixes {
+		listed, err := storage.Files.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, listed...)
+	}
+
+	referenced, err := referencedUploadKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UploadSweepReport{DryRun: dryRun, Scanned: len(objects), Orphaned: []string{}}
+	cutoff := time.Now().Add(-uploadSweepGracePeriod)
+	for _, obj := range objects {
+		if referenced[obj.Key] {
+			report.Referenced++
+			continue
+		}
+		if obj.ModTime.After(cutoff) {
+			continue
+		}
+
+		if !dryRun {
+			if err := storage.Files.Delete(ctx, obj.Key); err != nil {
+				log.Printf("Upload sweeper: failed to delete %s: %v", obj.Key, err)
+				report.Failed = append(report.Failed, obj.Key)
+				continue
+			}
+		}
+		report.Orphaned = append(report.Orphaned, obj.Key)
+		report.FreedBytes += obj.Size
+	}
+
+	return report, nil
+}
+
+// StartUploadSweeper periodically removes orphaned uploads.
+// Set UPLOAD_SWEEP_DRY_RUN=true to only log what would be removed.
+func StartUploadSweeper(interval time.Duration) {
+	dryRun, _ := strconv.ParseBool(os.Getenv("UPLOAD_SWEEP_DRY_RUN"))
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report, err := SweepOrphanedUploads(context.Background(), dryRun)
+			if err != nil {
+				log.Println("Upload sweeper:", err)
+				continue
+			}
+			if len(report.Orphaned) > 0 {
+				verb := "removed"
+				if dryRun {
+					verb = "would remove"
+				}
+				log.Printf("Upload sweeper: %s %d orphaned files (%d bytes): %v", verb, len(report.Orphaned), report.FreedBytes, report.Orphaned)
+			}
+		}
+	}()
+}
+
+// SweepUploads runs the orphaned upload sweep on demand, dry run unless ?dry_run=false (admin only)
+func SweepUploads(c *gin.Context) {
+	dryRun := true
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid dry_run"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := SweepOrphanedUploads(c.Request.Context(), dryRun)
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to sweep uploads").Wrap(err))
+		return
+	}
+	if !dryRun {
+		recordAudit(c, "uploads.sweep", "uploads", "", nil, map[string]interface{}{
+			"removed":     len(report.Orphaned),
+			"failed":      len(report.Failed),
+			"freed_bytes": report.FreedBytes,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
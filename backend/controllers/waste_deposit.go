@@ -1,168 +1,270 @@
 package controllers
 
 import (
+	"backend-api/apperr"
 	"backend-api/config"
 	"backend-api/models"
-	"fmt"
+	"encoding/json"
+	"log"
 	"net/http"
-	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// WasteDepositInput is accepted as JSON or as multipart form (with an optional photo file)
 type WasteDepositInput struct {
-	SchoolName   string `json:"school_name" binding:"required"`
-	ContactName  string `json:"contact_name" binding:"required"`
-	ContactPhone string `json:"contact_phone" binding:"required"`
-	Address      string `json:"address" binding:"required"`
-	PickupDate   string `json:"pickup_date" binding:"required"`
-	BinCount     int    `json:"bin_count" binding:"required"`
-	WasteType    string `json:"waste_type" binding:"required"`
+	PickupAddressID string      `json:"pickup_address_id" form:"pickup_address_id"`
+	ContactName     string      `json:"contact_name" form:"contact_name" binding:"max=100"`
+	ContactPhone    string      `json:"contact_phone" form:"contact_phone" binding:"max=30"`
+	Address         string      `json:"address" form:"address" binding:"max=500"`
+	PickupDate      string      `json:"pickup_date" form:"pickup_date" binding:"required"` // DD/MM/YYYY
+	BinCount        json.Number `json:"bin_count" form:"bin_count" binding:"required"`
+	WasteType       string      `json:"waste_type" form:"waste_type" binding:"required,max=50"`
 }
 
-// CreateWasteDeposit creates a new waste deposit submission with photo
+// CreateWasteDeposit creates a new waste deposit submission with photo for the user's school
 func CreateWasteDeposit(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	user, ok := currentSchoolUser(c)
+	if !ok {
 		return
 	}
 
-	// Get form fields
-	schoolName := c.PostForm("school_name")
-	contactName := c.PostForm("contact_name")
-	contactPhone := c.PostForm("contact_phone")
-	address := c.PostForm("address")
-	pickupDateStr := c.PostForm("pickup_date")
-	binCountStr := c.PostForm("bin_count")
-	wasteType := c.PostForm("waste_type")
-
-	// Validate required fields
-	if schoolName == "" || contactName == "" || contactPhone == "" || address == "" || pickupDateStr == "" || binCountStr == "" || wasteType == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "All fields are required"})
-		return
+	// Binding and rule errors are reported together so the client can flag every field at once
+	var input WasteDepositInput
+	fields, params := apperr.Fields{}, apperr.FieldParams{}
+	if err := c.ShouldBind(&input); err != nil {
+		fields, params = apperr.BindingFields(err)
+		if _, malformed := fields["body"]; malformed {
+			apperr.Abort(c, apperr.Validation(fields))
+			return
+		}
 	}
 
-	// Parse pickup date
-	pickupDate, err := time.Parse("02/01/2006", pickupDateStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use DD/MM/YYYY"})
-		return
+	valid, ruleFields, ruleParams := validateDepositInput(&input)
+	for field, message := range ruleFields {
+		if _, exists := fields[field]; !exists {
+			fields[field], params[field] = message, ruleParams[field]
+		}
 	}
-
-	// Parse bin count
-	var binCount int
-	if _, err := fmt.Sscanf(binCountStr, "%d", &binCount); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bin_count"})
+	if len(fields) > 0 {
+		apperr.Abort(c, apperr.Validation(fields).WithFieldParams(params))
 		return
 	}
 
+	// Deposits always belong to the user's own school
 	deposit := models.WasteDeposit{
-		UserID:       userID.(uuid.UUID),
-		SchoolName:   schoolName,
-		ContactName:  contactName,
-		ContactPhone: contactPhone,
-		Address:      address,
-		PickupDate:   pickupDate,
-		BinCount:     binCount,
-		WasteType:    wasteType,
+		UserID:       user.ID,
+		SchoolID:     user.SchoolID,
+		SchoolName:   user.SchoolName,
+		ContactName:  input.ContactName,
+		ContactPhone: valid.ContactPhone,
+		Address:      input.Address,
+		PickupDate:   valid.PickupDate,
+		BinCount:     valid.BinCount,
+		WasteType:    input.WasteType,
 		Status:       "pending",
 	}
 
-	// Handle photo upload
-	file, err := c.FormFile("photo")
-	if err == nil && file != nil {
-		// Create uploads directory if not exists
-		uploadDir := "uploads/deposits"
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+	// A saved pickup address (or the school's default when no address is typed) is copied onto the deposit
+	if input.PickupAddressID != "" || input.Address == "" {
+		saved, err := findPickupAddress(*user.SchoolID, input.PickupAddressID)
+		if err != nil {
+			apperr.Abort(c, apperr.Validation(apperr.Fields{"pickup_address_id": "pickup address not found"}))
 			return
 		}
+		if saved != nil {
+			deposit.PickupAddressID = &saved.ID
+			deposit.Address = saved.Address
+			deposit.ContactName = saved.ContactName
+			deposit.ContactPhone = saved.ContactPhone
+			deposit.Latitude = saved.Latitude
+			deposit.Longitude = saved.Longitude
+		}
+	}
 
-		// Generate unique filename
-		ext := filepath.Ext(file.Filename)
-		filename := uuid.New().String() + "_" + time.Now().Format("20060102150405") + ext
-		filePath := filepath.Join(uploadDir, filename)
+	fields = apperr.Fields{}
+	if deposit.ContactName == "" {
+		fields["contact_name"] = "is required"
+	}
+	if deposit.ContactPhone == "" {
+		fields["contact_phone"] = "is required"
+	}
+	if deposit.Address == "" {
+		fields["address"] = "is required"
+	}
+	if len(fields) > 0 {
+		apperr.Abort(c, apperr.Validation(fields))
+		return
+	}
 
-		// Save file
-		if err := c.SaveUploadedFile(file, filePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+	// Handle photo upload
+	file, err := c.FormFile("photo")
+	if err == nil && file != nil {
+		photoURL, thumbnails, err := saveUploadedImage(c, file, "deposits", uuid.New().String()+"_"+time.Now().Format("20060102150405"), true)
+		if err != nil {
+			respondUploadError(c, err, "Failed to save file")
 			return
 		}
 
-		// Use forward slashes for URL compatibility
-		deposit.PhotoProof = "/" + strings.ReplaceAll(filePath, "\\", "/")
+		deposit.PhotoProof = photoURL
+		deposit.PhotoThumbnails = thumbnails
 	}
 
 	if err := config.DB.Create(&deposit).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create deposit"})
+		deleteStoredFiles(deposit.PhotoProof, deposit.PhotoThumbnails)
+		apperr.Abort(c, apperr.Internal("Failed to create deposit"))
 		return
 	}
 
 	// Create notification for the user
-	title := "Penyetoran Berhasil"
-	message := fmt.Sprintf("Penyetoran %s %d tong berhasil dibuat dan menunggu konfirmasi", deposit.WasteType, deposit.BinCount)
-	CreateNotification(deposit.UserID, &deposit.ID, title, message, "deposit_update")
+	CreateNotification(deposit.UserID, &deposit.ID, "deposit.created", depositNotificationParams(deposit), "deposit_status")
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Waste deposit created successfully",
-		"deposit": deposit,
+		"deposit": withSignedPhoto(c.Request.Context(), deposit),
 	})
 }
 
+// depositNotificationParams are the template parameters shared by deposit notifications
+func depositNotificationParams(deposit models.WasteDeposit) map[string]string {
+	params := map[string]string{
+		"waste_type": deposit.WasteType,
+		"bin_count":  strconv.Itoa(deposit.BinCount),
+		"picker":     deposit.PickerName,
+	}
+	if deposit.Weight != nil {
+		params["weight"] = strconv.FormatFloat(*deposit.Weight, 'f', 1, 64)
+	}
+	return params
+}
+
 // GetMyDeposits returns all deposits for the authenticated user
 func GetMyDeposits(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var deposits []models.WasteDeposit
 	if err := config.DB.Where("user_id = ?", userID.(uuid.UUID)).Order("created_at DESC").Find(&deposits).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deposits"})
+		apperr.Abort(c, apperr.Internal("Failed to fetch deposits").Wrap(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"deposits": deposits,
+		"deposits": withSignedPhotos(c.Request.Context(), deposits),
 	})
 }
 
-// GetDepositByID returns a single deposit by ID
+// GetDepositByID returns a single deposit by ID to its owner or a coordinator of its school
 func GetDepositByID(c *gin.Context) {
 	depositID := c.Param("id")
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
+	currentUserID := userID.(uuid.UUID)
+
 	var deposit models.WasteDeposit
-	if err := config.DB.Where("id = ? AND user_id = ?", depositID, userID.(uuid.UUID)).First(&deposit).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Deposit not found"})
+	if err := config.DB.Where("id = ?", depositID).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+	// Coordinators see their school's deposits too, so they can follow disputes they raised
+	if !canViewDeposit(currentUserID, deposit) {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
 		return
 	}
 
+	dispute, err := latestDispute(deposit.ID)
+	if err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch disputes").Wrap(err))
+		return
+	}
+	if dispute != nil {
+		signed := withSignedDispute(c.Request.Context(), *dispute)
+		dispute = &signed
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"deposit": deposit,
+		"deposit": withSignedPhoto(c.Request.Context(), deposit),
+		"dispute": dispute,
 	})
 }
 
-// GetAllDeposits returns all deposits (admin only)
+// applyDepositFilters narrows an admin deposit query using ?status=, ?waste_type=, ?school_id=,
+// ?school_name=, ?user_id=, ?check_in_flagged= and ?from=/?to= (YYYY-MM-DD on created_at). Writes a 400 and returns ok=false on invalid input.
+func applyDepositFilters(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if status := c.Query("status"); status != "" {
+		query = query.Where("waste_deposits.status = ?", status)
+	}
+	if wasteType := c.Query("waste_type"); wasteType != "" {
+		query = query.Where("waste_deposits.waste_type = ?", wasteType)
+	}
+	if schoolID := c.Query("school_id"); schoolID != "" {
+		query = query.Where("waste_deposits.school_id = ?", schoolID)
+	}
+	if schoolName := c.Query("school_name"); schoolName != "" {
+		query = query.Where("waste_deposits.school_name ILIKE ?", "%"+schoolName+"%")
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userUUID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid user_id"))
+			return nil, false
+		}
+		query = query.Where("waste_deposits.user_id = ?", userUUID)
+	}
+	if flaggedStr := c.Query("check_in_flagged"); flaggedStr != "" {
+		flagged, err := strconv.ParseBool(flaggedStr)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid check_in_flagged"))
+			return nil, false
+		}
+		query = query.Where("waste_deposits.check_in_flagged = ?", flagged)
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.ParseInLocation("2006-01-02", fromStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid from date. Use YYYY-MM-DD"))
+			return nil, false
+		}
+		query = query.Where("waste_deposits.created_at >= ?", from)
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.ParseInLocation("2006-01-02", toStr, jakartaLoc)
+		if err != nil {
+			apperr.Abort(c, apperr.BadRequest("Invalid to date. Use YYYY-MM-DD"))
+			return nil, false
+		}
+		query = query.Where("waste_deposits.created_at < ?", to.AddDate(0, 0, 1))
+	}
+	return query, true
+}
+
+// GetAllDeposits returns all deposits matching the filters in applyDepositFilters (admin only)
 func GetAllDeposits(c *gin.Context) {
+	query, ok := applyDepositFilters(c, config.DB.Model(&models.WasteDeposit{}))
+	if !ok {
+		return
+	}
+
 	var deposits []models.WasteDeposit
-	if err := config.DB.Preload("User").Order("created_at DESC").Find(&deposits).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deposits"})
+	if err := query.Preload("User").Order("created_at DESC").Find(&deposits).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch deposits").Wrap(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"deposits": deposits,
+		"deposits": withSignedPhotos(c.Request.Context(), deposits),
 	})
 }
 
@@ -173,16 +275,19 @@ func UpdateDepositStatus(c *gin.Context) {
 	// Get admin user ID from context
 	adminUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var input struct {
 		Status string   `json:"status"`
 		Weight *float64 `json:"weight"`
+		// Completing without a QR check-in needs an explicit override and a reason, both kept in the audit log
+		OverrideCheckIn bool   `json:"override_check_in"`
+		OverrideReason  string `json:"override_reason" binding:"max=500"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperr.Abort(c, apperr.Binding(err))
 		return
 	}
 
@@ -197,23 +302,61 @@ func UpdateDepositStatus(c *gin.Context) {
 			}
 		}
 		if !isValid {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Must be: pending, proses, completed, or rejected"})
+			apperr.Abort(c, apperr.BadRequest("Invalid status. Must be: pending, proses, completed, or rejected"))
 			return
 		}
 	}
 
 	var deposit models.WasteDeposit
 	if err := config.DB.Where("id = ?", depositID).First(&deposit).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Deposit not found"})
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
 		return
 	}
 
 	oldStatus := deposit.Status
+	auditBefore := depositAuditFields(deposit)
+
+	// Once completed, a deposit keeps its status and its weight only changes through an approved weight correction
+	wasCompleted := oldStatus == "completed" || deposit.CompletedAt != nil
+	if wasCompleted && input.Status != "" && input.Status != oldStatus {
+		apperr.Abort(c, apperr.Conflict("A completed deposit cannot change status").WithCode(apperr.CodeDepositCompleted))
+		return
+	}
+	weightChanged := input.Weight != nil && (deposit.Weight == nil || *deposit.Weight != *input.Weight)
+	if weightChanged && wasCompleted {
+		apperr.Abort(c, apperr.Conflict("The weight of a completed deposit can only be changed through a weight correction").WithCode(apperr.CodeCorrectionRequired))
+		return
+	}
+	// The picker's QR check-in is the proof of the visit
+	overrideReason := strings.TrimSpace(input.OverrideReason)
+	checkInOverridden := false
+	if input.Status == "completed" && oldStatus != "completed" && deposit.CheckedInAt == nil {
+		if !input.OverrideCheckIn {
+			apperr.Abort(c, apperr.Conflict("The picker must check in with the school's QR code before the deposit can be completed").WithCode(apperr.CodeCheckInRequired))
+			return
+		}
+		if overrideReason == "" {
+			apperr.Abort(c, apperr.Validation(apperr.Fields{"override_reason": "is required"}))
+			return
+		}
+		checkInOverridden = true
+	}
 	
 	// Update status if provided
 	if input.Status != "" && input.Status != oldStatus {
 		deposit.Status = input.Status
-		
+
+		// Record when the deposit reached this status
+		now := time.Now().In(jakartaLoc)
+		switch input.Status {
+		case "proses":
+			deposit.ProcessedAt = &now
+		case "completed":
+			deposit.CompletedAt = &now
+		case "rejected":
+			deposit.RejectedAt = &now
+		}
+
 		// Set picker info when status changes to "proses"
 		if input.Status == "proses" {
 			adminID := adminUserID.(uuid.UUID)
@@ -225,42 +368,62 @@ func UpdateDepositStatus(c *gin.Context) {
 		}
 		
 		// Create notification for status change
-		var title, message string
+		var templateKey string
 		switch input.Status {
 		case "proses":
-			title = "Penyetoran Sedang Diproses"
-			message = fmt.Sprintf("Sampah %s %d tong sedang dalam proses penjemputan oleh %s", deposit.WasteType, deposit.BinCount, deposit.PickerName)
+			templateKey = "deposit.processing"
 		case "completed":
-			title = "Penyaluran Berhasil"
-			message = fmt.Sprintf("Sampah %s %d tong telah selesai diproses", deposit.WasteType, deposit.BinCount)
+			templateKey = "deposit.completed"
 		case "rejected":
-			title = "Penyetoran Ditolak"
-			message = fmt.Sprintf("Sampah %s %d tong tidak dapat diproses", deposit.WasteType, deposit.BinCount)
+			templateKey = "deposit.rejected"
 		}
-		
-		if title != "" {
-			CreateNotification(deposit.UserID, &deposit.ID, title, message, "deposit_update")
+
+		if templateKey != "" {
+			CreateNotification(deposit.UserID, &deposit.ID, templateKey, depositNotificationParams(deposit), "deposit_status")
 		}
 	}
 	
-	// Update weight if provided
-	if input.Weight != nil {
+	// Update weight if it changed
+	if weightChanged {
 		deposit.Weight = input.Weight
 		
 		// Create notification for weight update
-		title := "Berat Sampah Dikonfirmasi"
-		message := fmt.Sprintf("Berat sampah Anda telah dikonfirmasi: %.1f Kg", *input.Weight)
-		CreateNotification(deposit.UserID, &deposit.ID, title, message, "deposit_update")
+		CreateNotification(deposit.UserID, &deposit.ID, "deposit.weight_confirmed", depositNotificationParams(deposit), "weight_confirmed")
 	}
 
 	if err := config.DB.Save(&deposit).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update deposit"})
+		apperr.Abort(c, apperr.Internal("Failed to update deposit").Wrap(err))
 		return
 	}
+	auditAfter := depositAuditFields(deposit)
+	if checkInOverridden {
+		auditAfter["check_in_override"] = overrideReason
+	}
+	recordAudit(c, "deposit.update", "deposit", deposit.ID.String(), auditBefore, auditAfter)
+
+	// Award or adjust points now that status/weight may have changed
+	delta, err := syncDepositPoints(config.DB, deposit)
+	if err != nil {
+		log.Printf("Failed to update points for deposit %s: %v", deposit.ID, err)
+	} else if delta > 0 {
+		params := depositNotificationParams(deposit)
+		params["points"] = strconv.Itoa(delta)
+		CreateNotification(deposit.UserID, &deposit.ID, "points.awarded", params, "points")
+	} else if delta < 0 {
+		params := depositNotificationParams(deposit)
+		params["points"] = strconv.Itoa(delta)
+		CreateNotification(deposit.UserID, &deposit.ID, "points.adjusted", params, "points")
+	}
+
+	// Rankings and monthly reports only change when a completed deposit is involved
+	if deposit.Status == "completed" || oldStatus == "completed" {
+		go refreshLeaderboards()
+		invalidateMonthlyReport(deposit)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Deposit updated successfully",
-		"deposit": deposit,
+		"deposit": withSignedPhoto(c.Request.Context(), deposit),
 	})
 }
 
@@ -270,51 +433,48 @@ func UploadDepositPhoto(c *gin.Context) {
 	
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
 		return
 	}
 
 	// Get deposit
 	var deposit models.WasteDeposit
 	if err := config.DB.Where("id = ? AND user_id = ?", depositID, userID.(uuid.UUID)).First(&deposit).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Deposit not found"})
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
 		return
 	}
 
 	// Get uploaded file
 	file, err := c.FormFile("photo")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		apperr.Abort(c, apperr.BadRequest("No file uploaded"))
 		return
 	}
 
-	// Create uploads directory if not exists
-	uploadDir := "uploads/deposits"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+	photoURL, thumbnails, err := saveUploadedImage(c, file, "deposits", deposit.ID.String()+"_"+time.Now().Format("20060102150405"), true)
+	if err != nil {
+		respondUploadError(c, err, "Failed to save file")
 		return
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(file.Filename)
-	filename := deposit.ID.String() + "_" + time.Now().Format("20060102150405") + ext
-	filePath := filepath.Join(uploadDir, filename)
-
-	// Save file
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
-		return
-	}
+	oldPhoto, oldThumbnails := deposit.PhotoProof, deposit.PhotoThumbnails
 
 	// Update deposit with photo path
-	deposit.PhotoProof = "/" + filePath
+	deposit.PhotoProof = photoURL
+	deposit.PhotoThumbnails = thumbnails
 	if err := config.DB.Save(&deposit).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update deposit"})
+		deleteStoredFiles(photoURL, thumbnails)
+		apperr.Abort(c, apperr.Internal("Failed to update deposit"))
 		return
 	}
 
+	// The old photo is only removed once the new one is saved
+	deleteStoredFiles(oldPhoto, oldThumbnails)
+
+	signed := withSignedPhoto(c.Request.Context(), deposit)
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "Photo uploaded successfully",
-		"photo_path": deposit.PhotoProof,
+		"message":          "Photo uploaded successfully",
+		"photo_path":       signed.PhotoProof,
+		"photo_thumbnails": signed.PhotoThumbnails,
 	})
 }
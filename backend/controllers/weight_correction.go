@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var weightCorrectionStatuses = []string{"pending", "approved", "rejected"}
+
+// formatWeight renders a weight the way deposit notifications show it
+func formatWeight(weight *float64) string {
+	if weight == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*weight, 'f', 1, 64)
+}
+
+// requestWeightCorrection opens a pending correction for a completed deposit.
+// A deposit can only have one pending correction at a time.
+func requestWeightCorrection(tx *gorm.DB, deposit models.WasteDeposit, weight float64, reason string, requestedBy uuid.UUID) (*models.WeightCorrection, *apperr.Error) {
+	if deposit.Status != "completed" {
+		return nil, apperr.BadRequest("Only completed deposits need a weight correction")
+	}
+	if deposit.Weight != nil && *deposit.Weight == weight {
+		return nil, apperr.BadRequest("The deposit already has this weight")
+	}
+
+	var pending int64
+	if err := tx.Model(&models.WeightCorrection{}).Where("deposit_id = ? AND status = ?", deposit.ID, "pending").Count(&pending).Error; err != nil {
+		return nil, apperr.Internal("Failed to create weight correction").Wrap(err)
+	}
+	if pending > 0 {
+		return nil, apperr.Conflict("This deposit already has a pending weight correction")
+	}
+
+	correction := models.WeightCorrection{
+		DepositID:   deposit.ID,
+		OldWeight:   deposit.Weight,
+		NewWeight:   weight,
+		Reason:      reason,
+		Status:      "pending",
+		RequestedBy: requestedBy,
+	}
+	if err := tx.Create(&correction).Error; err != nil {
+		return nil, apperr.Internal("Failed to create weight correction").Wrap(err)
+	}
+	return &correction, nil
+}
+
+// RequestWeightCorrection proposes a new weight for a completed deposit (admin only).
+// The weight is applied once another admin approves it.
+func RequestWeightCorrection(c *gin.Context) {
+	adminUserID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+
+	var input struct {
+		Weight *float64 `json:"weight" binding:"required"`
+		Reason string   `json:"reason" binding:"required,max=500"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apperr.Abort(c, apperr.Binding(err))
+		return
+	}
+	if *input.Weight <= 0 {
+		apperr.Abort(c, apperr.Validation(apperr.Fields{"weight": "must be greater than 0"}))
+		return
+	}
+
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	correction, appErr := requestWeightCorrection(config.DB, deposit, *input.Weight, strings.TrimSpace(input.Reason), adminUserID.(uuid.UUID))
+	if appErr != nil {
+		apperr.Abort(c, appErr)
+		return
+	}
+	recordAudit(c, "weight_correction.request", "deposit", deposit.ID.String(),
+		map[string]interface{}{"weight": deposit.Weight},
+		map[string]interface{}{"weight": correction.NewWeight, "correction_id": correction.ID.String(), "reason": correction.Reason})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Weight correction submitted for approval",
+		"correction": correction,
+	})
+}
+
+// GetWeightCorrections lists weight corrections, oldest pending first, filtered by ?status= with ?page= and ?limit= (admin only)
+func GetWeightCorrections(c *gin.Context) {
+	page, limit, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.WeightCorrection{})
+	if status := c.Query("status"); status != "" {
+		if !containsString(weightCorrectionStatuses, status) {
+			apperr.Abort(c, apperr.BadRequest("Invalid status. Must be: pending, approved, or rejected"))
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch weight corrections").Wrap(err))
+		return
+	}
+
+	var corrections []models.WeightCorrection
+	if err := query.Preload("Deposit").Preload("Requester").Preload("Reviewer").
+		Order("status = 'pending' DESC, created_at").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&corrections).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch weight corrections").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"corrections": corrections,
+		"page":        page,
+		"limit":       limit,
+		"total":       total,
+	})
+}
+
+// GetDepositWeightCorrections returns the weight history of a deposit, newest first (admin only)
+func GetDepositWeightCorrections(c *gin.Context) {
+	var deposit models.WasteDeposit
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&deposit).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+
+	var corrections []models.WeightCorrection
+	if err := config.DB.Where("deposit_id = ?", deposit.ID).
+		Preload("Requester").Preload("Reviewer").
+		Order("created_at DESC").
+		Find(&corrections).Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to fetch weight corrections").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deposit_id":  deposit.ID,
+		"weight":      deposit.Weight,
+		"corrections": corrections,
+	})
+}
+
+// ApproveWeightCorrection applies a pending correction (admin only, not the requester)
+func ApproveWeightCorrection(c *gin.Context) {
+	reviewWeightCorrection(c, true)
+}
+
+// RejectWeightCorrection closes a pending correction without changing the deposit (admin only, not the requester)
+func RejectWeightCorrection(c *gin.Context) {
+	reviewWeightCorrection(c, false)
+}
+
+// reviewWeightCorrection approves or rejects a pending correction. On approval the deposit weight and point
+// ledger are updated in one transaction and the user gets a single notification with the corrected weight.
+func reviewWeightCorrection(c *gin.Context, approve bool) {
+	adminUserID, exists := c.Get("user_id")
+	if !exists {
+		apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+		return
+	}
+	adminID := adminUserID.(uuid.UUID)
+
+	var input struct {
+		Note string `json:"note" binding:"max=500"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			apperr.Abort(c, apperr.Binding(err))
+			return
+		}
+	}
+
+	var correction models.WeightCorrection
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&correction).Error; err != nil {
+		apperr.Abort(c, apperr.NotFound("Weight correction not found"))
+		return
+	}
+	if correction.Status != "pending" {
+		apperr.Abort(c, apperr.Conflict("Weight correction has already been reviewed"))
+		return
+	}
+	if correction.RequestedBy == adminID {
+		apperr.Abort(c, apperr.Forbidden("A weight correction must be reviewed by a different admin").WithCode(apperr.CodeSelfReview))
+		return
+	}
+
+	status, action := "rejected", "weight_correction.reject"
+	if approve {
+		status, action = "approved", "weight_correction.approve"
+	}
+	now := time.Now().In(jakartaLoc)
+
+	tx := config.DB.Begin()
+	// Only one reviewer can move the correction out of pending
+	result := tx.Model(&models.WeightCorrection{}).
+		Where("id = ? AND status = ?", correction.ID, "pending").
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": adminID,
+			"review_note": strings.TrimSpace(input.Note),
+			"reviewed_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to review weight correction").Wrap(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Conflict("Weight correction has already been reviewed"))
+		return
+	}
+
+	var deposit models.WasteDeposit
+	if err := tx.Where("id = ?", correction.DepositID).First(&deposit).Error; err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.NotFound("Deposit not found"))
+		return
+	}
+	oldWeight := deposit.Weight
+
+	delta := 0
+	if approve {
+		weight := correction.NewWeight
+		deposit.Weight = &weight
+		if err := tx.Save(&deposit).Error; err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.Internal("Failed to update deposit").Wrap(err))
+			return
+		}
+		var err error
+		if delta, err = syncDepositPoints(tx, deposit); err != nil {
+			tx.Rollback()
+			apperr.Abort(c, apperr.Internal("Failed to update points").Wrap(err))
+			return
+		}
+	}
+
+	dispute, err := settleDisputeForCorrection(tx, correction.ID, approve, now)
+	if err != nil {
+		tx.Rollback()
+		apperr.Abort(c, apperr.Internal("Failed to review weight correction").Wrap(err))
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		apperr.Abort(c, apperr.Internal("Failed to review weight correction").Wrap(err))
+		return
+	}
+
+	recordAudit(c, action, "deposit", deposit.ID.String(),
+		map[string]interface{}{"weight": oldWeight, "correction_status": "pending"},
+		map[string]interface{}{"weight": deposit.Weight, "correction_status": status, "correction_id": correction.ID.String()})
+
+	if approve {
+		params := depositNotificationParams(deposit)
+		params["old_weight"] = formatWeight(oldWeight)
+		params["points"] = fmt.Sprintf("%+d", delta)
+		CreateNotification(deposit.UserID, &deposit.ID, "deposit.weight_corrected", params, "weight_confirmed")
+		go refreshLeaderboards()
+		invalidateMonthlyReport(deposit)
+	}
+
+	// A dispute accepted with this weight is only now settled, either way
+	if dispute != nil {
+		disputeAction, templateKey := "dispute.reject", "dispute.rejected"
+		if approve {
+			disputeAction, templateKey = "dispute.resolve", "dispute.resolved"
+		}
+		recordAudit(c, disputeAction, "dispute", dispute.ID.String(),
+			map[string]interface{}{"status": "correction_pending"},
+			map[string]interface{}{"status": dispute.Status, "weight_correction_id": correction.ID.String()})
+		note := strings.TrimSpace(input.Note)
+		if note == "" {
+			note = dispute.ResolutionNote
+		}
+		notifyDisputeReviewed(dispute.RaisedBy, deposit, templateKey, note)
+	}
+
+	config.DB.Preload("Requester").Preload("Reviewer").First(&correction, "id = ?", correction.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Weight correction " + status + " successfully",
+		"correction": correction,
+		"deposit":    withSignedPhoto(c.Request.Context(), deposit),
+	})
+}
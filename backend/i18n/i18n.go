@@ -0,0 +1,92 @@
+// Package i18n holds the server's message catalogue in Indonesian and English.
+//
+// Notifications are looked up by template key ("deposit.created.title") with named
+// parameters written as {name} in the text. API error messages are written in English
+// in the code and translated by their English text, falling back to English when no
+// translation exists. Error messages with values in them use the same {name} placeholders,
+// so the lookup is by the template text and the values are filled in afterwards.
+package i18n
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	Indonesian = "id"
+	English    = "en"
+
+	// Default is used when neither the user nor the request names a supported locale
+	Default = Indonesian
+)
+
+var Locales = []string{Indonesian, English}
+
+var templates = map[string]map[string]string{
+	Indonesian: templatesID,
+	English:    templatesEN,
+}
+
+// Supported reports whether locale has a catalogue
+func Supported(locale string) bool {
+	_, ok := templates[locale]
+	return ok
+}
+
+// T renders the template key in locale, falling back to the default locale and then to the key itself
+func T(locale, key string, params map[string]string) string {
+	text, ok := templates[locale][key]
+	if !ok {
+		text, ok = templates[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	return fill(text, params)
+}
+
+// fill replaces the {name} placeholders in text with params
+func fill(text string, params map[string]string) string {
+	for name, value := range params {
+		text = strings.ReplaceAll(text, "{"+name+"}", value)
+	}
+	return text
+}
+
+// Message translates an English API message into locale, returning it unchanged when there is no translation
+func Message(locale, message string) string {
+	if locale == English {
+		return message
+	}
+	if translated, ok := messagesID[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Messagef translates message like Message, then fills its {name} placeholders from params
+func Messagef(locale, message string, params map[string]string) string {
+	return fill(Message(locale, message), params)
+}
+
+// ParseAcceptLanguage picks the supported locale with the highest weight from an Accept-Language header.
+// Returns an empty string when none of the listed languages is supported.
+func ParseAcceptLanguage(header string) string {
+	best, bestWeight := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = parsed
+			}
+		}
+
+		// "id-ID" and "en-US" match their base language
+		lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if Supported(lang) && weight > bestWeight {
+			best, bestWeight = lang, weight
+		}
+	}
+	return best
+}
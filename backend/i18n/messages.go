@@ -0,0 +1,235 @@
+package i18n
+
+// Indonesian translations of API error messages and validation field messages, keyed by the English text
+var messagesID = map[string]string{
+	// Generic
+	"Unauthorized":                                    "Tidak memiliki akses",
+	"Something went wrong":                            "Terjadi kesalahan pada server",
+	"Validation failed":                               "Data tidak valid",
+	"Too many requests, please slow down":             "Terlalu banyak permintaan, coba lagi nanti",
+	"Invalid page":                                    "Nomor halaman tidak valid",
+	"Invalid limit":                                   "Batas jumlah data tidak valid",
+	"Invalid user_id":                                 "user_id tidak valid",
+	"Invalid or expired link":                         "Tautan tidak valid atau sudah kedaluwarsa",
+	"File not found":                                  "File tidak ditemukan",
+	"Failed to read file":                             "Gagal membaca file",
+	"Failed to save file":                             "Gagal menyimpan file",
+	"Failed to save picture":                          "Gagal menyimpan foto",
+	"No file uploaded":                                "Tidak ada file yang diunggah",
+	"Unsupported file type. Allowed: JPEG, PNG, WebP": "Jenis file tidak didukung. Gunakan JPEG, PNG, atau WebP",
+	"File too large. Maximum size is {size} MB":       "File terlalu besar. Ukuran maksimal {size} MB",
+	"Image dimensions are too large. Maximum is 40 megapixels": "Ukuran gambar terlalu besar. Maksimal 40 megapiksel",
+
+	// Validation fields
+	"is required":                                           "wajib diisi",
+	"is invalid":                                            "tidak valid",
+	"must be a valid email address":                         "harus berupa alamat email yang valid",
+	"must be a whole number":                                "harus berupa bilangan bulat",
+	"must not be in the past":                               "tidak boleh tanggal yang sudah lewat",
+	"must be a date in DD/MM/YYYY format":                   "harus berupa tanggal dengan format DD/MM/YYYY",
+	"must be an Indonesian phone number, e.g. 081234567890": "harus nomor telepon Indonesia, contoh 081234567890",
+	"pickup address not found":                              "alamat penjemputan tidak ditemukan",
+	"must be a {type}":                                      "harus bertipe {type}",
+	"must be at most {max} characters":                      "maksimal {max} karakter",
+	"must be at least {min}":                                "minimal {min}",
+	"must be at least {min} characters":                     "minimal {min} karakter",
+	"must be at most {max}":                                 "maksimal {max}",
+	"must be at most {max} items":                           "maksimal {max} item",
+	"must be at least {min} items":                          "minimal {min} item",
+	"must be one of: {values}":                              "harus salah satu dari: {values}",
+	"must be between {min} and {max}":                       "harus di antara {min} dan {max}",
+	"must be within {days} days":                            "paling lambat {days} hari ke depan",
+
+	// Auth and users
+	"Authorization header required": "Header Authorization wajib dikirim",
+	"Invalid token format":          "Format token tidak valid",
+	"Invalid or expired token":      "Token tidak valid atau sudah kedaluwarsa",
+	"Admin access required":         "Hanya admin yang dapat mengakses",
+	"Email already exists":          "Email sudah terdaftar",
+	"Invalid email or password":     "Email atau password salah",
+	"This account was created with Google. Please use 'Sign in with Google' button": "Akun ini dibuat dengan Google. Silakan masuk dengan tombol 'Sign in with Google'",
+	"User not found":           "User tidak ditemukan",
+	"Failed to create user":    "Gagal membuat akun",
+	"Failed to update user":    "Gagal memperbarui user",
+	"Failed to hash password":  "Gagal memproses password",
+	"Failed to generate token": "Gagal membuat token",
+	"Failed to update profile": "Gagal memperbarui profil",
+
+	// Deposits
+	"Deposit not found":                   "Penyetoran tidak ditemukan",
+	"Photo not found":                     "Foto tidak ditemukan",
+	"Failed to create deposit":            "Gagal membuat penyetoran",
+	"Failed to fetch deposits":            "Gagal mengambil data penyetoran",
+	"Failed to update deposit":            "Gagal memperbarui penyetoran",
+	"Failed to export deposits":           "Gagal mengekspor penyetoran",
+	"Invalid date format. Use DD/MM/YYYY": "Format tanggal salah. Gunakan DD/MM/YYYY",
+	"Invalid status. Must be: pending, proses, completed, or rejected": "Status tidak valid. Pilih: pending, proses, completed, atau rejected",
+	"Invalid format. Must be: csv or xlsx":                             "Format tidak valid. Pilih: csv atau xlsx",
+	"Invalid from date. Use YYYY-MM-DD":                                "Tanggal from tidak valid. Gunakan YYYY-MM-DD",
+	"Invalid to date. Use YYYY-MM-DD":                                  "Tanggal to tidak valid. Gunakan YYYY-MM-DD",
+	"from must not be after to":                                        "Tanggal from tidak boleh setelah to",
+
+	// Pickup addresses and recurring pickups
+	"Pickup address not found":                                                  "Alamat penjemputan tidak ditemukan",
+	"You can only edit addresses you created":                                   "Anda hanya dapat mengubah alamat yang Anda buat",
+	"You can only delete addresses you created":                                 "Anda hanya dapat menghapus alamat yang Anda buat",
+	"Failed to fetch pickup addresses":                                          "Gagal mengambil alamat penjemputan",
+	"Failed to create pickup address":                                           "Gagal menyimpan alamat penjemputan",
+	"Failed to update pickup address":                                           "Gagal memperbarui alamat penjemputan",
+	"Failed to delete pickup address":                                           "Gagal menghapus alamat penjemputan",
+	"Failed to set default pickup address":                                      "Gagal menjadikan alamat penjemputan default",
+	"Recurring pickup not found":                                                "Jadwal rutin tidak ditemukan",
+	"Failed to fetch recurring pickups":                                         "Gagal mengambil jadwal rutin",
+	"Failed to create recurring pickup":                                         "Gagal membuat jadwal rutin",
+	"Failed to update recurring pickup":                                         "Gagal memperbarui jadwal rutin",
+	"Failed to delete recurring pickup":                                         "Gagal menghapus jadwal rutin",
+	"Failed to pause recurring pickup":                                          "Gagal menghentikan jadwal rutin",
+	"Failed to resume recurring pickup":                                         "Gagal melanjutkan jadwal rutin",
+	"Failed to skip occurrence":                                                 "Gagal melewati jadwal",
+	"Invalid start_date format. Use DD/MM/YYYY":                                 "Format start_date salah. Gunakan DD/MM/YYYY",
+	"Date is not an upcoming occurrence of this schedule":                       "Tanggal bukan jadwal berikutnya dari jadwal rutin ini",
+	"pickup_address_id or address, contact_name and contact_phone are required": "pickup_address_id atau address, contact_name, dan contact_phone wajib diisi",
+
+	// Schools
+	"School not found":                                             "Sekolah tidak ditemukan",
+	"Member not found":                                             "Anggota tidak ditemukan",
+	"Invalid school id":                                            "ID sekolah tidak valid",
+	"Set your school in your profile first":                        "Atur sekolah di profil Anda terlebih dahulu",
+	"You are not a member of this school":                          "Anda bukan anggota sekolah ini",
+	"Only admins and school coordinators can edit this school":     "Hanya admin dan koordinator sekolah yang dapat mengubah sekolah ini",
+	"Only admins and school coordinators can change member roles":  "Hanya admin dan koordinator sekolah yang dapat mengubah peran anggota",
+	"Invalid role. Must be: member or coordinator":                 "Peran tidak valid. Pilih: member atau coordinator",
+	"No schools to merge":                                          "Tidak ada sekolah untuk digabungkan",
+	"name is required":                                             "name wajib diisi",
+	"school_id is required":                                        "school_id wajib diisi",
+	"Failed to fetch schools":                                      "Gagal mengambil data sekolah",
+	"Failed to fetch members":                                      "Gagal mengambil data anggota",
+	"Failed to update school":                                      "Gagal memperbarui sekolah",
+	"Your request to join the school is waiting for approval":      "Permintaan bergabung ke sekolah masih menunggu persetujuan",
+	"Only admins and school coordinators can review join requests": "Hanya admin dan koordinator sekolah yang bisa meninjau permintaan bergabung",
+	"Join request not found":                                       "Permintaan bergabung tidak ditemukan",
+	"Failed to fetch join requests":                                "Gagal mengambil permintaan bergabung",
+	"Failed to update member":                                      "Gagal memperbarui anggota",
+	"School request not found":                                     "Permintaan sekolah baru tidak ditemukan",
+	"Failed to fetch school requests":                              "Gagal mengambil permintaan sekolah baru",
+	"Failed to update members":                                     "Gagal memperbarui anggota",
+	"Failed to create school":                                      "Gagal membuat sekolah",
+	"Failed to assign a coordinator":                               "Gagal menetapkan koordinator",
+	"Failed to approve school request":                             "Gagal menyetujui permintaan sekolah baru",
+	"Failed to reject school request":                              "Gagal menolak permintaan sekolah baru",
+	"Failed to merge schools":                                      "Gagal menggabungkan sekolah",
+	"Failed to create school, NPSN may already be registered":      "Gagal membuat sekolah, NPSN mungkin sudah terdaftar",
+	"Failed to update school, NPSN may already be registered":      "Gagal memperbarui sekolah, NPSN mungkin sudah terdaftar",
+
+	// Notifications
+	"Invalid category: {category}":   "Kategori tidak valid: {category}",
+	"Invalid channel: {channel}":     "Kanal tidak valid: {channel}",
+	"Notification not found":         "Notifikasi tidak ditemukan",
+	"Invalid is_read":                "is_read tidak valid",
+	"Provide ids or all_read":        "Kirim ids atau all_read",
+	"Failed to fetch notifications":  "Gagal mengambil notifikasi",
+	"Failed to update notification":  "Gagal memperbarui notifikasi",
+	"Failed to update notifications": "Gagal memperbarui notifikasi",
+	"Failed to delete notification":  "Gagal menghapus notifikasi",
+	"Failed to delete notifications": "Gagal menghapus notifikasi",
+	"Failed to fetch preferences":    "Gagal mengambil preferensi",
+	"Failed to update preferences":   "Gagal memperbarui preferensi",
+
+	// Chat
+	"Message must be at most {max} characters":       "Pesan maksimal {max} karakter",
+	"Message not found":                              "Pesan tidak ditemukan",
+	"Message is required":                            "Pesan wajib diisi",
+	"Message has been deleted":                       "Pesan sudah dihapus",
+	"Message can no longer be edited":                "Pesan sudah tidak dapat diubah",
+	"Message can no longer be deleted":               "Pesan sudah tidak dapat dihapus",
+	"Invalid message cursor":                         "Cursor pesan tidak valid",
+	"Use either before or after, not both":           "Gunakan before atau after, tidak keduanya",
+	"Invalid type. Must be: text, image, or deposit": "Tipe tidak valid. Pilih: text, image, atau deposit",
+	"Invalid deposit_id":                             "deposit_id tidak valid",
+	"Recipient not found":                            "Penerima tidak ditemukan",
+	"Cannot send a message to yourself":              "Tidak dapat mengirim pesan ke diri sendiri",
+	"Cannot block yourself":                          "Tidak dapat memblokir diri sendiri",
+	"You are not allowed to send messages":           "Anda tidak diizinkan mengirim pesan",
+	"You can only send messages to admins":           "Anda hanya dapat mengirim pesan ke admin",
+	"This user is not accepting your messages":       "User ini tidak menerima pesan dari Anda",
+	"Report not found":                               "Laporan tidak ditemukan",
+	"Failed to fetch messages":                       "Gagal mengambil pesan",
+	"Failed to send message":                         "Gagal mengirim pesan",
+	"Failed to edit message":                         "Gagal mengubah pesan",
+	"Failed to delete message":                       "Gagal menghapus pesan",
+	"Failed to update messages":                      "Gagal memperbarui pesan",
+	"Failed to block user":                           "Gagal memblokir user",
+	"Failed to unblock user":                         "Gagal membuka blokir user",
+	"Failed to create report":                        "Gagal membuat laporan",
+	"Failed to fetch reports":                        "Gagal mengambil laporan",
+	"Failed to update report":                        "Gagal memperbarui laporan",
+
+	// Announcements
+	"Announcement not found":                                            "Pengumuman tidak ditemukan",
+	"Scheduled announcement not found":                                  "Pengumuman terjadwal tidak ditemukan",
+	"Invalid scheduled_at. Use RFC3339, e.g. 2024-12-24T08:00:00+07:00": "scheduled_at tidak valid. Gunakan RFC3339, contoh 2024-12-24T08:00:00+07:00",
+	"target_role is required for role announcements":                    "target_role wajib diisi untuk pengumuman per role",
+	"target_schools is required for school announcements":               "target_schools wajib diisi untuk pengumuman per sekolah",
+	"target_schools must be existing school IDs":                        "target_schools harus berisi ID sekolah yang terdaftar",
+	"Failed to create announcement":                                     "Gagal membuat pengumuman",
+	"Failed to fetch announcements":                                     "Gagal mengambil pengumuman",
+	"Failed to cancel announcement":                                     "Gagal membatalkan pengumuman",
+
+	// Statistics, reports and maintenance
+	"Invalid period. Must be: week, month, year, or all": "Periode tidak valid. Pilih: week, month, year, atau all",
+	"Invalid metric. Must be: weight or points":          "Metrik tidak valid. Pilih: weight atau points",
+	"Invalid interval. Must be: day, week, or month":     "Interval tidak valid. Pilih: day, week, atau month",
+	"Invalid month. Use YYYY-MM":                         "Bulan tidak valid. Gunakan YYYY-MM",
+	"Invalid dry_run":                                    "dry_run tidak valid",
+	"Failed to compute impact":                           "Gagal menghitung dampak",
+	"Failed to compute leaderboard":                      "Gagal menghitung peringkat",
+	"Failed to compute statistics":                       "Gagal menghitung statistik",
+	"Failed to generate report":                          "Gagal membuat laporan",
+	"Failed to sweep uploads":                            "Gagal membersihkan file upload",
+
+	// Weight corrections
+	"A completed deposit cannot change status":                                          "Status penyetoran yang sudah selesai tidak bisa diubah",
+	"The weight of a completed deposit can only be changed through a weight correction": "Berat penyetoran yang sudah selesai hanya bisa diubah lewat koreksi berat",
+	"Only completed deposits need a weight correction":                                  "Koreksi berat hanya untuk penyetoran yang sudah selesai",
+	"The deposit already has this weight":                                               "Penyetoran sudah memiliki berat ini",
+	"This deposit already has a pending weight correction":                              "Penyetoran ini masih memiliki koreksi berat yang menunggu persetujuan",
+	"Invalid status. Must be: pending, approved, or rejected":                           "Status tidak valid. Pilih: pending, approved, atau rejected",
+	"Weight correction not found":                                                       "Koreksi berat tidak ditemukan",
+	"Weight correction has already been reviewed":                                       "Koreksi berat sudah ditinjau",
+	"A weight correction must be reviewed by a different admin":                         "Koreksi berat harus ditinjau oleh admin lain",
+	"Failed to create weight correction":                                                "Gagal membuat koreksi berat",
+	"Failed to fetch weight corrections":                                                "Gagal mengambil koreksi berat",
+	"Failed to review weight correction":                                                "Gagal meninjau koreksi berat",
+	"Failed to update points":                                                           "Gagal memperbarui poin",
+	"must be greater than 0":                                                            "harus lebih dari 0",
+
+	// Disputes
+	"Only completed deposits with a confirmed weight can be disputed":          "Hanya penyetoran selesai dengan berat terkonfirmasi yang bisa disanggah",
+	"This deposit already has an open dispute":                                 "Penyetoran ini masih memiliki sanggahan yang belum ditinjau",
+	"Invalid status. Must be: open, correction_pending, resolved, or rejected": "Status tidak valid. Pilih: open, correction_pending, resolved, atau rejected",
+	"Dispute not found":                 "Sanggahan tidak ditemukan",
+	"Dispute has already been reviewed": "Sanggahan sudah ditinjau",
+	"Failed to create dispute":          "Gagal membuat sanggahan",
+	"Failed to fetch disputes":          "Gagal mengambil sanggahan",
+	"Failed to review dispute":          "Gagal meninjau sanggahan",
+	"must be at most {max} files":       "maksimal {max} file",
+
+	// QR check-in
+	"The picker can't check in with a QR code they opened themselves":                        "Penjemput tidak bisa check-in dengan kode QR yang dibukanya sendiri",
+	"The picker must check in with the school's QR code before the deposit can be completed": "Penjemput harus check-in dengan kode QR sekolah sebelum penyetoran bisa diselesaikan",
+	"Invalid check_in_flagged":                     "check_in_flagged tidak valid",
+	"This deposit is no longer waiting for pickup": "Penyetoran ini sudah tidak menunggu penjemputan",
+	"Failed to generate QR code":                   "Gagal membuat kode QR",
+	"This QR code belongs to a different deposit":  "Kode QR ini milik penyetoran lain",
+	"Invalid QR code":                              "Kode QR tidak valid",
+	"This QR code has already been used":           "Kode QR ini sudah pernah dipakai",
+	"This deposit is assigned to another picker":   "Penyetoran ini ditugaskan ke penjemput lain",
+	"Failed to check in deposit":                   "Gagal mencatat kedatangan penjemput",
+	"latitude and longitude must be sent together": "latitude dan longitude harus dikirim bersamaan",
+	"must be between -90 and 90":                   "harus di antara -90 dan 90",
+	"must be between -180 and 180":                 "harus di antara -180 dan 180",
+
+	// Audit log
+	"Invalid actor_id":          "actor_id tidak valid",
+	"Failed to fetch audit log": "Gagal mengambil log audit",
+}
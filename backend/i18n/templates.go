@@ -0,0 +1,64 @@
+package i18n
+
+// Notification templates. Every notification key has a .title and a .message entry.
+var templatesID = map[string]string{
+	"deposit.created.title":            "Penyetoran Berhasil",
+	"deposit.created.message":          "Penyetoran {waste_type} {bin_count} tong berhasil dibuat dan menunggu konfirmasi",
+	"deposit.recurring.title":          "Penjemputan Terjadwal",
+	"deposit.recurring.message":        "Penyetoran rutin {waste_type} {bin_count} tong dijadwalkan pada {date} dan menunggu konfirmasi",
+	"deposit.recurring_failed.title":   "Penjemputan Rutin Gagal Dijadwalkan",
+	"deposit.recurring_failed.message": "Penyetoran rutin {waste_type} {bin_count} tong untuk {date} belum bisa dibuat. Periksa sekolah dan alamat penjemputan pada jadwal Anda",
+	"deposit.processing.title":         "Penyetoran Sedang Diproses",
+	"deposit.processing.message":       "Sampah {waste_type} {bin_count} tong sedang dalam proses penjemputan oleh {picker}",
+	"deposit.completed.title":          "Penyaluran Berhasil",
+	"deposit.completed.message":        "Sampah {waste_type} {bin_count} tong telah selesai diproses",
+	"deposit.rejected.title":           "Penyetoran Ditolak",
+	"deposit.rejected.message":         "Sampah {waste_type} {bin_count} tong tidak dapat diproses",
+	"deposit.weight_confirmed.title":   "Berat Sampah Dikonfirmasi",
+	"deposit.weight_confirmed.message": "Berat sampah Anda telah dikonfirmasi: {weight} Kg",
+	"deposit.weight_corrected.title":   "Berat Sampah Dikoreksi",
+	"deposit.weight_corrected.message": "Berat sampah {waste_type} Anda dikoreksi dari {old_weight} Kg menjadi {weight} Kg, poin disesuaikan {points}",
+	"dispute.resolved.title":           "Sanggahan Diterima",
+	"dispute.resolved.message":         "Sanggahan untuk penyetoran {waste_type} {bin_count} tong diterima: {note}",
+	"dispute.rejected.title":           "Sanggahan Ditolak",
+	"dispute.rejected.message":         "Sanggahan untuk penyetoran {waste_type} {bin_count} tong ditolak: {note}",
+	"points.awarded.title":             "Poin Bertambah",
+	"points.awarded.message":           "Anda mendapatkan {points} poin dari penyetoran sampah {waste_type}",
+	"points.adjusted.title":            "Poin Disesuaikan",
+	"points.adjusted.message":          "Poin Anda disesuaikan {points} karena perubahan data penyetoran",
+	"chat.new_message.title":           "Pesan baru dari {sender}",
+	"chat.new_message.message":         "{text}",
+	"chat.new_photo.title":             "Pesan baru dari {sender}",
+	"chat.new_photo.message":           "Mengirim foto",
+}
+
+var templatesEN = map[string]string{
+	"deposit.created.title":            "Deposit Submitted",
+	"deposit.created.message":          "Your deposit of {bin_count} bins of {waste_type} was created and is awaiting confirmation",
+	"deposit.recurring.title":          "Pickup Scheduled",
+	"deposit.recurring.message":        "Recurring deposit of {bin_count} bins of {waste_type} is scheduled for {date} and awaiting confirmation",
+	"deposit.recurring_failed.title":   "Recurring Pickup Not Scheduled",
+	"deposit.recurring_failed.message": "The recurring deposit of {bin_count} bins of {waste_type} for {date} could not be created. Check the school and pickup address of your schedule",
+	"deposit.processing.title":         "Deposit In Progress",
+	"deposit.processing.message":       "{bin_count} bins of {waste_type} are being picked up by {picker}",
+	"deposit.completed.title":          "Deposit Completed",
+	"deposit.completed.message":        "{bin_count} bins of {waste_type} have been processed",
+	"deposit.rejected.title":           "Deposit Rejected",
+	"deposit.rejected.message":         "{bin_count} bins of {waste_type} could not be processed",
+	"deposit.weight_confirmed.title":   "Weight Confirmed",
+	"deposit.weight_confirmed.message": "The weight of your waste has been confirmed: {weight} kg",
+	"deposit.weight_corrected.title":   "Weight Corrected",
+	"deposit.weight_corrected.message": "The weight of your {waste_type} deposit was corrected from {old_weight} kg to {weight} kg, points adjusted by {points}",
+	"dispute.resolved.title":           "Dispute Accepted",
+	"dispute.resolved.message":         "Your dispute about the deposit of {bin_count} bins of {waste_type} was accepted: {note}",
+	"dispute.rejected.title":           "Dispute Rejected",
+	"dispute.rejected.message":         "Your dispute about the deposit of {bin_count} bins of {waste_type} was rejected: {note}",
+	"points.awarded.title":             "Points Earned",
+	"points.awarded.message":           "You earned {points} points from your {waste_type} deposit",
+	"points.adjusted.title":            "Points Adjusted",
+	"points.adjusted.message":          "Your points were adjusted by {points} after a change to a deposit",
+	"chat.new_message.title":           "New message from {sender}",
+	"chat.new_message.message":         "{text}",
+	"chat.new_photo.title":             "New message from {sender}",
+	"chat.new_photo.message":           "Sent a photo",
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"backend-api/config"
+	"backend-api/controllers"
+	"backend-api/middlewares"
 	"backend-api/models"
 	"backend-api/routes"
+	"backend-api/storage"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -18,18 +22,48 @@ func main() {
 
 	config.ConnectDatabase()
 
-	if err := config.DB.AutoMigrate(&models.User{}, &models.WasteDeposit{}, &models.Notification{}, &models.ChatMessage{}); err != nil {
+	if err := config.DB.AutoMigrate(
+		&models.School{},
+		&models.User{},
+		&models.PickupAddress{},
+		&models.WasteDeposit{},
+		&models.WeightCorrection{},
+		&models.DepositDispute{},
+		&models.RecurringPickup{},
+		&models.PointTransaction{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.Announcement{},
+		&models.ChatMessage{},
+		&models.ChatBlock{},
+		&models.ChatReport{},
+		&models.AuditLog{},
+	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+	// Notifications created before categories existed all used the deposit_update type
+	config.DB.Model(&models.Notification{}).Where("type = ?", "deposit_update").Update("type", "deposit_status")
 	log.Println("Database migration completed")
 
+	controllers.MigrateSchools()
+	controllers.BackfillDepositPoints()
+
+	storage.Setup()
+
+	controllers.StartAnnouncementScheduler(time.Minute)
+	controllers.StartNotificationRetention(24 * time.Hour)
+	controllers.StartUploadSweeper(24 * time.Hour)
+	controllers.StartMonthlyReportJob(6 * time.Hour)
+	controllers.StartRecurringPickupScheduler(time.Hour)
+
 	r := gin.Default()
 	
 
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, Accept-Language")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -39,7 +73,9 @@ func main() {
 
 		c.Next()
 	})
-	
+
+	r.Use(middlewares.RequestIDMiddleware(), middlewares.LocaleMiddleware(), middlewares.ErrorMiddleware())
+
 	routes.SetupRoutes(r)
 
 	port := os.Getenv("PORT")
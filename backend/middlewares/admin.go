@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"backend-api/apperr"
+	"backend-api/config"
+	"backend-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminMiddleware only lets users with the admin role through. Must run after AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			apperr.Abort(c, apperr.Unauthorized("Unauthorized"))
+			return
+		}
+
+		var user models.User
+		if err := config.DB.Select("id", "role").Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err != nil {
+			apperr.Abort(c, apperr.Unauthorized("User not found"))
+			return
+		}
+
+		if user.Role != "admin" {
+			apperr.Abort(c, apperr.Forbidden("Admin access required").WithCode(apperr.CodeAdminRequired))
+			return
+		}
+
+		c.Next()
+	}
+}
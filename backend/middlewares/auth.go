@@ -1,8 +1,8 @@
 package middlewares
 
 import (
+	"backend-api/apperr"
 	"backend-api/utils"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -12,22 +12,19 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			apperr.Abort(c, apperr.Unauthorized("Authorization header required").WithCode(apperr.CodeTokenMissing))
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-			c.Abort()
+			apperr.Abort(c, apperr.Unauthorized("Invalid token format").WithCode(apperr.CodeTokenInvalid))
 			return
 		}
 
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			apperr.Abort(c, apperr.Unauthorized("Invalid or expired token").WithCode(apperr.CodeTokenInvalid))
 			return
 		}
 
@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"backend-api/apperr"
+	"backend-api/i18n"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware tags every request with an ID, taken from X-Request-ID when the client sends one.
+// The ID is echoed in the response header and in error bodies so reports can be matched to logs.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.New().String()
+		}
+		c.Set("request_id", requestID)
+		c.Writer.Header().Set(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// ErrorMiddleware renders errors recorded with apperr.Abort and recovers from panics.
+// Messages are translated into the request locale. Every error response has the same shape:
+//
+//	{"error": "Deposit not found", "code": "not_found", "fields": {...}, "request_id": "..."}
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				c.Abort()
+				writeError(c, apperr.Internal("Something went wrong").Wrap(fmt.Errorf("panic: %v", r)))
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeError(c, c.Errors.Last().Err)
+	}
+}
+
+func writeError(c *gin.Context, err error) {
+	e := apperr.From(err)
+	requestID := c.GetString("request_id")
+
+	if e.Status >= http.StatusInternalServerError {
+		log.Printf("[%s] %s %s: %v", requestID, c.Request.Method, c.Request.URL.Path, e)
+	}
+
+	locale := c.GetString("locale")
+	body := gin.H{
+		"error":      i18n.Messagef(locale, e.Message, e.Params),
+		"code":       e.Code,
+		"request_id": requestID,
+	}
+	if len(e.Fields) > 0 {
+		fields := make(apperr.Fields, len(e.Fields))
+		for field, message := range e.Fields {
+			fields[field] = i18n.Messagef(locale, message, e.FieldParams[field])
+		}
+		body["fields"] = fields
+	}
+	c.JSON(e.Status, body)
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"backend-api/config"
+	"backend-api/i18n"
+	"backend-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LocaleMiddleware picks the response language from Accept-Language, defaulting to Indonesian
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		if locale == "" {
+			locale = i18n.Default
+		}
+		c.Set("locale", locale)
+		c.Next()
+	}
+}
+
+// UserLocaleMiddleware lets the language saved on the user's profile override Accept-Language.
+// Must run after AuthMiddleware.
+func UserLocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, exists := c.Get("user_id"); exists {
+			var user models.User
+			if err := config.DB.Select("id", "locale").Where("id = ?", userID.(uuid.UUID)).First(&user).Error; err == nil && i18n.Supported(user.Locale) {
+				c.Set("locale", user.Locale)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"backend-api/apperr"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware allows each authenticated user at most limit requests per window.
+// Counters are kept in memory, so limits apply per server instance.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	hits := make(map[string][]time.Time)
+	lastPrune := time.Now()
+
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		key := fmt.Sprint(userID)
+		now := time.Now()
+
+		mu.Lock()
+		// Once per window, forget users whose last request fell out of it so the map doesn't grow forever
+		if now.Sub(lastPrune) >= window {
+			for k, times := range hits {
+				if len(times) == 0 || now.Sub(times[len(times)-1]) >= window {
+					delete(hits, k)
+				}
+			}
+			lastPrune = now
+		}
+
+		// Drop requests that fell out of the window
+		recent := hits[key][:0]
+		for _, t := range hits[key] {
+			if now.Sub(t) < window {
+				recent = append(recent, t)
+			}
+		}
+
+		if len(recent) >= limit {
+			retryAfter := window - now.Sub(recent[0])
+			hits[key] = recent
+			mu.Unlock()
+
+			c.Header("Retry-After", fmt.Sprintf("%d", int(retryAfter.Seconds())+1))
+			apperr.Abort(c, apperr.TooManyRequests("Too many requests, please slow down"))
+			return
+		}
+
+		hits[key] = append(recent, now)
+		mu.Unlock()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Announcement struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Title          string     `gorm:"not null" json:"title"`
+	Message        string     `gorm:"not null" json:"message"`
+	TargetType     string     `gorm:"not null" json:"target_type"` // all, role, schools
+	TargetRole     string     `json:"target_role,omitempty"`
+	TargetSchools  []string   `gorm:"serializer:json" json:"target_schools,omitempty"` // School IDs
+	Status         string     `gorm:"default:'scheduled'" json:"status"`               // scheduled, sending, sent, failed
+	ScheduledAt    time.Time  `gorm:"not null;index" json:"scheduled_at"`
+	ClaimedAt      *time.Time `json:"claimed_at"` // Last sign of life of the sender; a sending announcement that stops updating it is picked up again
+	SentAt         *time.Time `json:"sent_at"`
+	RecipientCount int        `json:"recipient_count"`
+	CreatedBy      uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	Creator        User       `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (a *Announcement) BeforeCreate(tx *gorm.DB) error {
+	a.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	a.CreatedAt = time.Now().In(loc)
+	a.UpdatedAt = time.Now().In(loc)
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrAuditLogAppendOnly = errors.New("audit log entries cannot be changed")
+
+// AuditLog records a privileged change. Before and After only hold the fields that changed.
+// Entries are append-only: updates and deletes are refused.
+type AuditLog struct {
+	ID         uuid.UUID              `gorm:"type:uuid;primary_key" json:"id"`
+	ActorID    uuid.UUID              `gorm:"type:uuid;not null;index" json:"actor_id"`
+	Actor      User                   `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Action     string                 `gorm:"not null;index" json:"action"`                       // e.g. deposit.update, school.merge
+	TargetType string                 `gorm:"not null;index:idx_audit_target" json:"target_type"` // deposit, school, user, announcement, chat_report, uploads
+	TargetID   string                 `gorm:"index:idx_audit_target" json:"target_id"`
+	Before     map[string]interface{} `gorm:"serializer:json" json:"before,omitempty"`
+	After      map[string]interface{} `gorm:"serializer:json" json:"after,omitempty"`
+	IP         string                 `json:"ip"`
+	UserAgent  string                 `json:"user_agent"`
+	RequestID  string                 `json:"request_id"`
+	CreatedAt  time.Time              `gorm:"index" json:"created_at"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	a.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	a.CreatedAt = time.Now().In(loc)
+	return nil
+}
+
+func (a *AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogAppendOnly
+}
+
+func (a *AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogAppendOnly
+}
@@ -8,14 +8,20 @@ import (
 )
 
 type ChatMessage struct {
-	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	SenderID   uuid.UUID `gorm:"type:uuid;not null" json:"sender_id"`
-	ReceiverID uuid.UUID `gorm:"type:uuid;not null" json:"receiver_id"`
-	Sender     User      `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
-	Receiver   User      `gorm:"foreignKey:ReceiverID" json:"receiver,omitempty"`
-	Message    string    `gorm:"not null" json:"message"`
-	IsRead     bool      `gorm:"default:false" json:"is_read"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID         uuid.UUID     `gorm:"type:uuid;primary_key" json:"id"`
+	SenderID   uuid.UUID     `gorm:"type:uuid;not null" json:"sender_id"`
+	ReceiverID uuid.UUID     `gorm:"type:uuid;not null" json:"receiver_id"`
+	Sender     User          `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
+	Receiver   User          `gorm:"foreignKey:ReceiverID" json:"receiver,omitempty"`
+	Type       string        `gorm:"default:'text'" json:"type"` // text, image, deposit
+	Message    string        `gorm:"not null" json:"message"`
+	Attachment string        `json:"attachment,omitempty"` // Path of uploaded image for type image
+	DepositID  *uuid.UUID    `gorm:"type:uuid" json:"deposit_id,omitempty"`
+	Deposit    *WasteDeposit `gorm:"foreignKey:DepositID" json:"deposit,omitempty"`
+	IsRead     bool          `gorm:"default:false" json:"is_read"`
+	EditedAt   *time.Time    `json:"edited_at"`
+	DeletedAt  *time.Time    `json:"deleted_at"` // Soft delete, message body is cleared
+	CreatedAt  time.Time     `json:"created_at"`
 }
 
 func (m *ChatMessage) BeforeCreate(tx *gorm.DB) error {
@@ -25,3 +31,42 @@ func (m *ChatMessage) BeforeCreate(tx *gorm.DB) error {
 	m.CreatedAt = time.Now().In(loc)
 	return nil
 }
+
+// ChatBlock prevents BlockedID from sending messages to BlockerID
+type ChatBlock struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	BlockerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_chat_block_pair" json:"blocker_id"`
+	BlockedID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_chat_block_pair" json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (b *ChatBlock) BeforeCreate(tx *gorm.DB) error {
+	b.ID = uuid.New()
+	return nil
+}
+
+// ChatReport is raised by a user against another user (and optionally a message) for admins to review
+type ChatReport struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primary_key" json:"id"`
+	ReporterID     uuid.UUID    `gorm:"type:uuid;not null" json:"reporter_id"`
+	Reporter       User         `gorm:"foreignKey:ReporterID" json:"reporter,omitempty"`
+	ReportedUserID uuid.UUID    `gorm:"type:uuid;not null" json:"reported_user_id"`
+	ReportedUser   User         `gorm:"foreignKey:ReportedUserID" json:"reported_user,omitempty"`
+	MessageID      *uuid.UUID   `gorm:"type:uuid" json:"message_id,omitempty"`
+	Message        *ChatMessage `gorm:"foreignKey:MessageID" json:"message,omitempty"`
+	Reason         string       `gorm:"not null" json:"reason"`
+	Status         string       `gorm:"default:'pending'" json:"status"` // pending, resolved, dismissed
+	ResolvedBy     *uuid.UUID   `gorm:"type:uuid" json:"resolved_by,omitempty"`
+	ResolutionNote string       `json:"resolution_note"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+func (r *ChatReport) BeforeCreate(tx *gorm.DB) error {
+	r.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	r.CreatedAt = time.Now().In(loc)
+	r.UpdatedAt = time.Now().In(loc)
+	return nil
+}
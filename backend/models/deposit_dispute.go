@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DepositDispute is raised by a school that disagrees with the confirmed weight of a completed deposit
+type DepositDispute struct {
+	ID                 uuid.UUID         `gorm:"type:uuid;primary_key" json:"id"`
+	DepositID          uuid.UUID         `gorm:"type:uuid;not null;index" json:"deposit_id"`
+	Deposit            *WasteDeposit     `gorm:"foreignKey:DepositID" json:"deposit,omitempty"`
+	RaisedBy           uuid.UUID         `gorm:"type:uuid;not null" json:"raised_by"`
+	Raiser             User              `gorm:"foreignKey:RaisedBy" json:"raiser,omitempty"`
+	Reason             string            `gorm:"not null" json:"reason"`
+	ClaimedWeight      *float64          `json:"claimed_weight"` // Weight the school believes is correct, optional
+	Photos             []string          `gorm:"serializer:json" json:"photos,omitempty"`
+	Status             string            `gorm:"default:'open';index" json:"status"` // open, correction_pending (accepted, waiting for the weight correction review), resolved, rejected
+	ResolvedBy         *uuid.UUID        `gorm:"type:uuid" json:"resolved_by"`
+	Resolver           *User             `gorm:"foreignKey:ResolvedBy" json:"resolver,omitempty"`
+	ResolutionNote     string            `json:"resolution_note"`
+	WeightCorrectionID *uuid.UUID        `gorm:"type:uuid" json:"weight_correction_id"` // Correction opened when resolving, still needs approval
+	WeightCorrection   *WeightCorrection `gorm:"foreignKey:WeightCorrectionID" json:"weight_correction,omitempty"`
+	ResolvedAt         *time.Time        `json:"resolved_at"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+func (d *DepositDispute) BeforeCreate(tx *gorm.DB) error {
+	d.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	d.CreatedAt = time.Now().In(loc)
+	d.UpdatedAt = time.Now().In(loc)
+	return nil
+}
@@ -8,15 +8,18 @@ import (
 )
 
 type Notification struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	User      User      `gorm:"foreignKey:UserID" json:"-"`
-	DepositID *uuid.UUID `gorm:"type:uuid" json:"deposit_id,omitempty"`
-	Title     string    `gorm:"not null" json:"title"`
-	Message   string    `gorm:"not null" json:"message"`
-	Type      string    `gorm:"default:'deposit_update'" json:"type"`
-	IsRead    bool      `gorm:"default:false" json:"is_read"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             uuid.UUID         `gorm:"type:uuid;primary_key" json:"id"`
+	UserID         uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
+	User           User              `gorm:"foreignKey:UserID" json:"-"`
+	DepositID      *uuid.UUID        `gorm:"type:uuid" json:"deposit_id,omitempty"`
+	AnnouncementID *uuid.UUID        `gorm:"type:uuid;index" json:"announcement_id,omitempty"`
+	Title          string            `gorm:"not null" json:"title"`   // Rendered in the default locale when TemplateKey is set
+	Message        string            `gorm:"not null" json:"message"` // Rendered in the default locale when TemplateKey is set
+	TemplateKey    string            `json:"template_key,omitempty"`  // i18n key without the .title/.message suffix, empty for free text
+	TemplateParams map[string]string `gorm:"serializer:json" json:"template_params,omitempty"`
+	Type           string            `gorm:"default:'deposit_status'" json:"type"` // deposit_status, weight_confirmed, chat, points, announcement
+	IsRead         bool              `gorm:"default:false" json:"is_read"`
+	CreatedAt      time.Time         `json:"created_at"`
 }
 
 func (n *Notification) BeforeCreate(tx *gorm.DB) error {
@@ -26,3 +29,19 @@ func (n *Notification) BeforeCreate(tx *gorm.DB) error {
 	n.CreatedAt = time.Now().In(loc)
 	return nil
 }
+
+// NotificationPreference stores whether a user wants a notification category on a channel.
+// Missing rows fall back to the defaults in controllers.
+type NotificationPreference struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preference" json:"-"`
+	Category  string    `gorm:"not null;uniqueIndex:idx_notification_preference" json:"category"`
+	Channel   string    `gorm:"not null;uniqueIndex:idx_notification_preference" json:"channel"` // in_app, push, email
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (p *NotificationPreference) BeforeCreate(tx *gorm.DB) error {
+	p.ID = uuid.New()
+	return nil
+}
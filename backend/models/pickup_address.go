@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PickupAddress is a saved pickup location shared by the members of a school
+type PickupAddress struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	SchoolID     uuid.UUID `gorm:"type:uuid;not null;index" json:"school_id"`
+	CreatedBy    uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	Label        string    `gorm:"not null" json:"label"` // e.g. Gerbang depan, Kantin
+	Address      string    `gorm:"not null" json:"address"`
+	ContactName  string    `gorm:"not null" json:"contact_name"`
+	ContactPhone string    `gorm:"not null" json:"contact_phone"`
+	Latitude     *float64  `json:"latitude"`
+	Longitude    *float64  `json:"longitude"`
+	IsDefault    bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (p *PickupAddress) BeforeCreate(tx *gorm.DB) error {
+	p.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	p.CreatedAt = time.Now().In(loc)
+	p.UpdatedAt = time.Now().In(loc)
+	return nil
+}
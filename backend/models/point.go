@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PointTransaction is an append-only ledger entry. A user's balance is the sum of their entries.
+type PointTransaction struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	DepositID *uuid.UUID `gorm:"type:uuid;index" json:"deposit_id,omitempty"`
+	Points    int        `gorm:"not null" json:"points"`
+	Reason    string     `gorm:"not null" json:"reason"` // deposit_completed, deposit_adjusted
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (p *PointTransaction) BeforeCreate(tx *gorm.DB) error {
+	p.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	p.CreatedAt = time.Now().In(loc)
+	return nil
+}
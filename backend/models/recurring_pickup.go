@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecurringPickup generates a pending WasteDeposit every IntervalWeeks weeks on Weekday
+type RecurringPickup struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	SchoolID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"school_id"`
+	Weekday         int        `gorm:"not null" json:"weekday"`                  // 0 = Minggu ... 6 = Sabtu
+	IntervalWeeks   int        `gorm:"not null;default:1" json:"interval_weeks"` // 1 = setiap minggu, 2 = dua minggu sekali
+	WasteType       string     `gorm:"not null" json:"waste_type"`
+	BinCount        int        `gorm:"not null" json:"bin_count"` // Perkiraan jumlah tong
+	PickupAddressID *uuid.UUID `gorm:"type:uuid" json:"pickup_address_id"`
+	Address         string     `json:"address"` // Dipakai jika tidak memakai alamat tersimpan
+	ContactName     string     `json:"contact_name"`
+	ContactPhone    string     `json:"contact_phone"`
+	NextDate        time.Time  `gorm:"not null;index" json:"next_date"`   // Jadwal berikutnya yang belum dibuat penyetorannya
+	SkipDates       []string   `gorm:"serializer:json" json:"skip_dates"` // YYYY-MM-DD
+	Paused          bool       `gorm:"default:false" json:"paused"`
+	LastError       string     `json:"last_error,omitempty"` // Kenapa penyetoran terakhir gagal dibuat, kosong jika berhasil
+	LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (r *RecurringPickup) BeforeCreate(tx *gorm.DB) error {
+	r.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	r.CreatedAt = time.Now().In(loc)
+	r.UpdatedAt = time.Now().In(loc)
+	return nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type School struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	Name         string    `gorm:"not null" json:"name"`
+	NPSN         *string   `gorm:"uniqueIndex" json:"npsn"` // Nomor Pokok Sekolah Nasional, kosong jika belum diketahui
+	Address      string    `json:"address"`
+	ContactName  string    `json:"contact_name"`
+	ContactPhone string    `json:"contact_phone"`
+	Latitude     *float64  `json:"latitude"`
+	Longitude    *float64  `json:"longitude"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (s *School) BeforeCreate(tx *gorm.DB) error {
+	s.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	s.CreatedAt = time.Now().In(loc)
+	s.UpdatedAt = time.Now().In(loc)
+	return nil
+}
@@ -8,15 +8,22 @@ import (
 )
 
 type User struct {
-	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	Name       string    `gorm:"not null" json:"name"`
-	Email      string    `gorm:"unique;not null" json:"email"`
-	Password   string    `gorm:"not null" json:"-"`
-	Picture    string    `json:"picture"`
-	SchoolName string    `json:"school_name"`
-	Role       string    `gorm:"default:'user'" json:"role"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                uuid.UUID         `gorm:"type:uuid;primary_key" json:"id"`
+	Name              string            `gorm:"not null" json:"name"`
+	Email             string            `gorm:"unique;not null" json:"email"`
+	Password          string            `gorm:"not null" json:"-"`
+	Picture           string            `json:"picture"`
+	PictureThumbnails map[string]string `gorm:"serializer:json" json:"picture_thumbnails,omitempty"`
+	SchoolName        string            `json:"school_name"` // Copy of School.Name, kept in sync
+	SchoolID          *uuid.UUID        `gorm:"type:uuid;index" json:"school_id"`
+	SchoolRole        string            `gorm:"default:'member'" json:"school_role"`      // member, coordinator
+	PendingSchoolID   *uuid.UUID        `gorm:"type:uuid;index" json:"pending_school_id"` // School the user asked to join, waiting for a coordinator or admin
+	RequestedSchool   string            `gorm:"index" json:"requested_school"`            // Name of a school not registered yet, waiting for an admin to add it
+	Role              string            `gorm:"default:'user'" json:"role"`
+	Locale            string            `json:"locale"`                            // id, en; empty follows the device language
+	ChatBlocked       bool              `gorm:"default:false" json:"chat_blocked"` // Set by admin after a chat report
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -8,23 +8,40 @@ import (
 )
 
 type WasteDeposit struct {
-	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	UserID       uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	User         User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	SchoolName   string    `gorm:"not null" json:"school_name"`
-	ContactName  string    `gorm:"not null" json:"contact_name"`
-	ContactPhone string    `gorm:"not null" json:"contact_phone"`
-	Address      string    `gorm:"not null" json:"address"`
-	PickupDate   time.Time `gorm:"not null" json:"pickup_date"`
-	BinCount     int       `gorm:"not null" json:"bin_count"`
-	WasteType    string    `gorm:"not null" json:"waste_type"`
-	PhotoProof   string    `json:"photo_proof"`
-	Weight       *float64   `json:"weight"`                          // Weight in kg, filled by admin
-	Status       string     `gorm:"default:'pending'" json:"status"` // pending, proses, completed, rejected
-	PickerID     *uuid.UUID `gorm:"type:uuid" json:"picker_id"`      // ID admin yang memproses
-	PickerName   string     `json:"picker_name"`                     // Nama penjemput/pengangkut (admin yang memproses)
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uuid.UUID         `gorm:"type:uuid;primary_key" json:"id"`
+	UserID            uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
+	User              User              `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	SchoolID          *uuid.UUID        `gorm:"type:uuid;index" json:"school_id"`
+	SchoolName        string            `gorm:"not null" json:"school_name"` // Copy of School.Name, kept in sync
+	ContactName       string            `gorm:"not null" json:"contact_name"`
+	ContactPhone      string            `gorm:"not null" json:"contact_phone"`
+	Address           string            `gorm:"not null" json:"address"`
+	Latitude          *float64          `json:"latitude"`
+	Longitude         *float64          `json:"longitude"`
+	PickupAddressID   *uuid.UUID        `gorm:"type:uuid" json:"pickup_address_id"`         // Alamat tersimpan yang dipakai, data alamat disalin ke penyetoran
+	RecurringPickupID *uuid.UUID        `gorm:"type:uuid;index" json:"recurring_pickup_id"` // Diisi jika dibuat dari jadwal rutin
+	PickupDate        time.Time         `gorm:"not null" json:"pickup_date"`
+	BinCount          int               `gorm:"not null" json:"bin_count"`
+	WasteType         string            `gorm:"not null" json:"waste_type"`
+	PhotoProof        string            `json:"photo_proof"`
+	PhotoThumbnails   map[string]string `gorm:"serializer:json" json:"photo_thumbnails,omitempty"` // small, medium
+	Weight            *float64          `json:"weight"`                                            // Weight in kg, filled by admin
+	Status            string            `gorm:"default:'pending'" json:"status"`                   // pending, proses, completed, rejected
+	PickerID          *uuid.UUID        `gorm:"type:uuid" json:"picker_id"`                        // ID admin yang memproses
+	PickerName        string            `json:"picker_name"`                                       // Nama penjemput/pengangkut (admin yang memproses)
+	ProcessedAt       *time.Time        `json:"processed_at"`                                      // Saat status menjadi proses
+	CompletedAt       *time.Time        `json:"completed_at"`                                      // Saat status menjadi completed
+	RejectedAt        *time.Time        `json:"rejected_at"`                                       // Saat status menjadi rejected
+	CheckInToken      string            `json:"-"`                                                 // Token QR yang ditunjukkan sekolah, hanya bisa dipakai sekali
+	CheckInFetchedBy  []string          `gorm:"serializer:json" json:"-"`                          // User yang pernah membuka QR, tidak boleh check-in sendiri
+	CheckedInAt       *time.Time        `json:"checked_in_at"`                                     // Saat penjemput memindai QR di sekolah
+	CheckedInBy       *uuid.UUID        `gorm:"type:uuid" json:"checked_in_by"`                    // Penjemput yang memindai QR
+	CheckInLatitude   *float64          `json:"check_in_latitude"`                                 // Lokasi penjemput saat memindai, opsional
+	CheckInLongitude  *float64          `json:"check_in_longitude"`
+	CheckInDistance   *float64          `json:"check_in_distance_m"`                         // Jarak lokasi pindai ke lokasi penjemputan dalam meter
+	CheckInFlagged    bool              `gorm:"default:false;index" json:"check_in_flagged"` // Lokasi pindai terlalu jauh atau tidak dikirim, perlu dicek admin
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
 }
 
 func (w *WasteDeposit) BeforeCreate(tx *gorm.DB) error {
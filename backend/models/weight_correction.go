@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WeightCorrection is a request to change the weight of a completed deposit.
+// It only takes effect once an admin other than the requester approves it; rows are kept as the weight history.
+type WeightCorrection struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primary_key" json:"id"`
+	DepositID   uuid.UUID     `gorm:"type:uuid;not null;index" json:"deposit_id"`
+	Deposit     *WasteDeposit `gorm:"foreignKey:DepositID" json:"deposit,omitempty"`
+	OldWeight   *float64      `json:"old_weight"` // Weight when the correction was requested
+	NewWeight   float64       `gorm:"not null" json:"new_weight"`
+	Reason      string        `gorm:"not null" json:"reason"`
+	Status      string        `gorm:"default:'pending';index" json:"status"` // pending, approved, rejected
+	RequestedBy uuid.UUID     `gorm:"type:uuid;not null" json:"requested_by"`
+	Requester   User          `gorm:"foreignKey:RequestedBy" json:"requester,omitempty"`
+	ReviewedBy  *uuid.UUID    `gorm:"type:uuid" json:"reviewed_by"`
+	Reviewer    *User         `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
+	ReviewNote  string        `json:"review_note"`
+	ReviewedAt  *time.Time    `json:"reviewed_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func (w *WeightCorrection) BeforeCreate(tx *gorm.DB) error {
+	w.ID = uuid.New()
+	// Set timezone to Jakarta (WIB/UTC+7)
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	w.CreatedAt = time.Now().In(loc)
+	w.UpdatedAt = time.Now().In(loc)
+	return nil
+}
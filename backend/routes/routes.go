@@ -3,6 +3,10 @@ package routes
 import (
 	"backend-api/controllers"
 	"backend-api/middlewares"
+	"backend-api/storage"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,36 +14,128 @@ import (
 func SetupRoutes(r *gin.Engine) {
 	r.POST("/register", controllers.Register)
 	r.POST("/login", controllers.Login)
+	// School search is public so the registration form can pick an existing school
+	r.GET("/schools", controllers.GetSchools)
 
-	// Serve uploaded files
-	r.Static("/uploads", "./uploads")
+	// Serve uploaded files when they are kept on local disk.
+	// Only profile pictures are public, other files need a signed URL.
+	if local, ok := storage.Files.(*storage.Local); ok {
+		r.GET(local.URLPrefix+"/*filepath", controllers.ServeUpload)
+	}
 
 	protected := r.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
+	protected.Use(middlewares.AuthMiddleware(), middlewares.UserLocaleMiddleware())
 	{
 		protected.GET("/me", controllers.GetMe)
 		protected.PUT("/profile", controllers.UpdateProfile)
+		protected.GET("/me/impact", controllers.GetMyImpact)
+		protected.GET("/leaderboard", controllers.GetLeaderboard)
+		protected.GET("/reports/monthly", controllers.GetMyMonthlyReport)
+
+		// Schools
+		protected.GET("/schools/:id", controllers.GetSchoolByID)
+		protected.PUT("/schools/:id", controllers.UpdateSchool)
+		protected.GET("/schools/:id/members", controllers.GetSchoolMembers)
+		protected.PUT("/schools/:id/members/:user_id", controllers.UpdateSchoolMember)
+		protected.GET("/schools/:id/join-requests", controllers.GetSchoolJoinRequests)
+		protected.POST("/schools/:id/join-requests/:user_id/approve", controllers.ApproveSchoolJoinRequest)
+		protected.POST("/schools/:id/join-requests/:user_id/reject", controllers.RejectSchoolJoinRequest)
+
+		// Saved pickup addresses of the user's school
+		protected.GET("/pickup-addresses", controllers.GetPickupAddresses)
+		protected.POST("/pickup-addresses", controllers.CreatePickupAddress)
+		protected.PUT("/pickup-addresses/:id", controllers.UpdatePickupAddress)
+		protected.DELETE("/pickup-addresses/:id", controllers.DeletePickupAddress)
+
+		// Recurring pickup schedules
+		protected.GET("/recurring-pickups", controllers.GetRecurringPickups)
+		protected.POST("/recurring-pickups", controllers.CreateRecurringPickup)
+		protected.PUT("/recurring-pickups/:id", controllers.UpdateRecurringPickup)
+		protected.DELETE("/recurring-pickups/:id", controllers.DeleteRecurringPickup)
+		protected.POST("/recurring-pickups/:id/pause", controllers.PauseRecurringPickup)
+		protected.POST("/recurring-pickups/:id/resume", controllers.ResumeRecurringPickup)
+		protected.POST("/recurring-pickups/:id/skip", controllers.SkipRecurringOccurrence)
 		
 		// Waste Deposit routes
 		protected.POST("/deposits", controllers.CreateWasteDeposit)
 		protected.GET("/deposits", controllers.GetMyDeposits)
 		protected.GET("/deposits/:id", controllers.GetDepositByID)
 		protected.POST("/deposits/:id/photo", controllers.UploadDepositPhoto)
+		protected.GET("/deposits/:id/photo", controllers.GetDepositPhoto)
+		protected.GET("/deposits/:id/photo-url", controllers.GetDepositPhotoURL)
+		protected.POST("/deposits/:id/disputes", controllers.CreateDispute)
+		protected.GET("/deposits/:id/qr", controllers.GetDepositQRCode)
 		
 		// Notification routes
 		protected.GET("/notifications", controllers.GetMyNotifications)
 		protected.GET("/notifications/unread-count", controllers.GetUnreadNotificationCount)
 		protected.PUT("/notifications/:id/read", controllers.MarkNotificationAsRead)
 		protected.PUT("/notifications/read-all", controllers.MarkAllNotificationsAsRead)
-		
-		// Admin routes
-		protected.GET("/admin/deposits", controllers.GetAllDeposits)
-		protected.PUT("/admin/deposits/:id/status", controllers.UpdateDepositStatus)
+		protected.DELETE("/notifications/:id", controllers.DeleteNotification)
+		protected.DELETE("/notifications", controllers.DeleteNotifications)
+		protected.GET("/notifications/preferences", controllers.GetNotificationPreferences)
+		protected.PUT("/notifications/preferences", controllers.UpdateNotificationPreferences)
 		
 		// Chat routes
 		protected.GET("/chat/list", controllers.GetChatList)
 		protected.GET("/chat/unread-count", controllers.GetUnreadCount)
 		protected.GET("/chat/:user_id/messages", controllers.GetMessages)
-		protected.POST("/chat/:user_id/messages", controllers.SendMessage)
+		protected.POST("/chat/:user_id/messages", middlewares.RateLimitMiddleware(chatRateLimit(), time.Minute), controllers.SendMessage)
+		protected.POST("/chat/:user_id/read", controllers.MarkMessagesAsRead)
+		protected.PUT("/chat/messages/:id", controllers.EditMessage)
+		protected.DELETE("/chat/messages/:id", controllers.DeleteMessage)
+		protected.POST("/chat/:user_id/block", controllers.BlockUser)
+		protected.DELETE("/chat/:user_id/block", controllers.UnblockUser)
+		protected.POST("/chat/:user_id/report", controllers.ReportUser)
+	}
+
+	admin := protected.Group("/admin")
+	admin.Use(middlewares.AdminMiddleware())
+	{
+		admin.GET("/deposits", controllers.GetAllDeposits)
+		admin.GET("/deposits/export", controllers.ExportDeposits)
+		admin.PUT("/deposits/:id/status", controllers.UpdateDepositStatus)
+		admin.POST("/deposits/:id/check-in", controllers.CheckInDeposit)
+		admin.GET("/deposits/:id/weight-corrections", controllers.GetDepositWeightCorrections)
+		admin.POST("/deposits/:id/weight-corrections", controllers.RequestWeightCorrection)
+		admin.GET("/weight-corrections", controllers.GetWeightCorrections)
+		admin.POST("/weight-corrections/:id/approve", controllers.ApproveWeightCorrection)
+		admin.POST("/weight-corrections/:id/reject", controllers.RejectWeightCorrection)
+		admin.GET("/disputes", controllers.GetDisputes)
+		admin.GET("/disputes/:id", controllers.GetDisputeByID)
+		admin.POST("/disputes/:id/resolve", controllers.ResolveDispute)
+		admin.POST("/disputes/:id/reject", controllers.RejectDispute)
+		admin.GET("/stats", controllers.GetAdminStats)
+		admin.GET("/reports/monthly", controllers.GetSchoolMonthlyReport)
+		admin.POST("/schools", controllers.CreateSchool)
+		admin.POST("/schools/:id/merge", controllers.MergeSchools)
+		admin.GET("/school-requests", controllers.GetSchoolRequests)
+		admin.POST("/school-requests/approve", controllers.ApproveSchoolRequest)
+		admin.POST("/school-requests/reject", controllers.RejectSchoolRequest)
+
+		// Chat moderation
+		admin.GET("/chat/reports", controllers.GetChatReports)
+		admin.PUT("/chat/reports/:id", controllers.ResolveChatReport)
+
+		// Announcements
+		admin.POST("/announcements", controllers.CreateAnnouncement)
+		admin.GET("/announcements", controllers.GetAnnouncements)
+		admin.GET("/announcements/:id", controllers.GetAnnouncementByID)
+		admin.DELETE("/announcements/:id", controllers.CancelAnnouncement)
+
+		// Uploads maintenance
+		admin.POST("/uploads/sweep", controllers.SweepUploads)
+
+		// Audit log
+		admin.GET("/audit", controllers.GetAuditLogs)
+	}
+}
+
+// chatRateLimit returns how many chat messages a user may send per minute
+func chatRateLimit() int {
+	limit, err := strconv.Atoi(os.Getenv("CHAT_RATE_LIMIT_PER_MINUTE"))
+	if err != nil || limit <= 0 {
+		limit = 20
 	}
+	return limit
 }
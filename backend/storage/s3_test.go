@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const testBucket = "test-bucket"
+
+// fakeS3 is a minimal in-memory stand-in for an S3 compatible service. It handles the
+// path-style requests minio-go makes for PutObject, HeadObject, GetObject, RemoveObject
+// and ListObjectsV2 on a single bucket.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+type listBucketResult struct {
+	XMLName     xml.Name          `xml:"ListBucketResult"`
+	Name        string            `xml:"Name"`
+	Prefix      string            `xml:"Prefix"`
+	KeyCount    int               `xml:"KeyCount"`
+	MaxKeys     int               `xml:"MaxKeys"`
+	IsTruncated bool              `xml:"IsTruncated"`
+	Contents    []listBucketEntry `xml:"Contents"`
+}
+
+type listBucketEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != testBucket {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodPut:
+		data, err := readS3Payload(r)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = fakeObject{data: data, contentType: r.Header.Get("Content-Type"), modTime: time.Now().UTC().Truncate(time.Second)}
+		w.Header().Set("ETag", `"`+strconv.Itoa(len(data))+`"`)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		obj, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", `"`+strconv.Itoa(len(obj.data))+`"`)
+		w.Header().Set("Content-Type", obj.contentType)
+		http.ServeContent(w, r, key, obj.modTime, bytes.NewReader(obj.data))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	result := listBucketResult{Name: testBucket, Prefix: prefix, MaxKeys: 1000}
+	for key, obj := range f.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		result.Contents = append(result.Contents, listBucketEntry{
+			Key:          key,
+			LastModified: obj.modTime.Format(time.RFC3339),
+			ETag:         `"` + strconv.Itoa(len(obj.data)) + `"`,
+			Size:         int64(len(obj.data)),
+			StorageClass: "STANDARD",
+		})
+	}
+	sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+// readS3Payload returns the object body, decoding the aws-chunked framing minio-go uses
+// for streaming signatures and trailing checksums
+func readS3Payload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") &&
+		!strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data []byte
+	body := bufio.NewReader(r.Body)
+	for {
+		line, err := body.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(body, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+		if _, err := body.ReadString('\n'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+func newTestS3(t *testing.T) *S3 {
+	t.Helper()
+
+	server := httptest.NewTLSServer(&fakeS3{objects: map[string]fakeObject{}})
+	t.Cleanup(server.Close)
+
+	endpoint, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:     credentials.NewStaticV4("test-access", "test-secret", ""),
+		Secure:    true,
+		Region:    "us-east-1",
+		Transport: server.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewS3(client, testBucket, server.URL+"/"+testBucket)
+}
+
+func putString(t *testing.T, s *S3, key, content string) {
+	t.Helper()
+	if err := s.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put(%q): %v", key, err)
+	}
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	s := newTestS3(t)
+	ctx := context.Background()
+
+	putString(t, s, "deposits/photo.jpg", "first")
+	putString(t, s, "deposits/photo.jpg", "second")
+
+	file, err := s.Get(ctx, "deposits/photo.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("reading object: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("Get returned %q, want the replaced content %q", data, "second")
+	}
+
+	if err := s.Delete(ctx, "deposits/photo.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "deposits/photo.jpg"); err != ErrNotFound {
+		t.Errorf("Get after Delete returned %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "deposits/photo.jpg"); err != nil {
+		t.Errorf("Delete of a missing key returned %v, want nil", err)
+	}
+}
+
+func TestS3List(t *testing.T) {
+	s := newTestS3(t)
+
+	putString(t, s, "reports/a/2024-01.pdf", "january")
+	putString(t, s, "reports/a/2024-02.pdf", "feb")
+	putString(t, s, "reports/b/2024-01.pdf", "other school")
+	putString(t, s, "deposits/photo.jpg", "photo")
+
+	objects, err := s.List(context.Background(), "reports/a/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("List returned %d objects, want 2: %+v", len(objects), objects)
+	}
+	if objects[0].Key != "reports/a/2024-01.pdf" || objects[0].Size != int64(len("january")) {
+		t.Errorf("first object is %+v, want reports/a/2024-01.pdf of %d bytes", objects[0], len("january"))
+	}
+	if objects[1].Key != "reports/a/2024-02.pdf" {
+		t.Errorf("second object is %q, want reports/a/2024-02.pdf", objects[1].Key)
+	}
+	if objects[0].ModTime.IsZero() {
+		t.Error("List did not report the modification time")
+	}
+}
+
+func TestS3URLAndKey(t *testing.T) {
+	s := NewS3(nil, testBucket, "https://cdn.example.com/files/")
+
+	url := s.URL("deposits/photo.jpg")
+	if url != "https://cdn.example.com/files/deposits/photo.jpg" {
+		t.Errorf("URL = %q", url)
+	}
+	if key, ok := s.Key(url + "?X-Amz-Signature=abc"); !ok || key != "deposits/photo.jpg" {
+		t.Errorf("Key(signed URL) = %q, %v", key, ok)
+	}
+	if _, ok := s.Key("https://elsewhere.example.com/deposits/photo.jpg"); ok {
+		t.Error("Key accepted a URL from another host")
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// ErrNotFound is returned by Get when no object exists under the key
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage is where uploaded files (profile pictures, deposit photos, chat attachments) are kept.
+// Keys are slash separated paths such as "deposits/<file>.jpg".
+type Storage interface {
+	// Put stores the content under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the URL saved on models and returned to clients for key
+	URL(key string) string
+	// SignedURL returns a URL for key that stops working after expiry
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// Key extracts the key from a URL returned by URL. ok is false for URLs from elsewhere.
+	Key(url string) (key string, ok bool)
+	// List returns every object whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Object describes a stored file returned by List
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Files is the storage backend used by the handlers, selected by Setup
+var Files Storage
+
+// Setup selects the backend from STORAGE_DRIVER (local or s3, default local)
+func Setup() {
+	driver := os.Getenv("STORAGE_DRIVER")
+	switch driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		Files = NewLocal(dir, "/uploads", signingKey())
+	case "s3":
+		s3, err := NewS3FromEnv()
+		if err != nil {
+			log.Fatal("Failed to set up S3 storage:", err)
+		}
+		Files = s3
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q", driver)
+	}
+
+	log.Printf("File storage: %s", driverName(driver))
+}
+
+func driverName(driver string) string {
+	if driver == "" {
+		return "local"
+	}
+	return driver
+}
+
+// signingKey is used to sign local URLs, falling back to the JWT secret
+func signingKey() []byte {
+	if key := os.Getenv("STORAGE_SIGNING_KEY"); key != "" {
+		return []byte(key)
+	}
+	return []byte(os.Getenv("JWT_SECRET"))
+}
@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+var (
+	ErrFileTooLarge     = errors.New("file too large")
+	ErrImageTooLarge    = errors.New("image dimensions too large")
+	ErrUnsupportedImage = errors.New("unsupported image type")
+)
+
+// MaxImagePixels caps width×height before decoding, so a small file can't expand into gigabytes of pixels
+const MaxImagePixels = 40_000_000
+
+// Content types accepted for uploads, detected from the file content rather than the client's extension
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// ProcessedImage is an upload decoded and re-encoded without any of the original metadata
+type ProcessedImage struct {
+	Image       image.Image
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+// ProcessImage reads at most maxSize bytes, checks the sniffed type against the allowlist and
+// re-encodes the image. Re-encoding drops EXIF (including GPS) and anything appended to the file.
+func ProcessImage(r io.Reader, maxSize int64) (*ProcessedImage, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	contentType := http.DetectContentType(raw)
+	if !allowedImageTypes[contentType] {
+		return nil, ErrUnsupportedImage
+	}
+
+	// Only the header is read here, the pixels are decoded once the size is known to be sane
+	var config image.Config
+	switch contentType {
+	case "image/webp":
+		config, err = webp.DecodeConfig(bytes.NewReader(raw))
+	default:
+		config, _, err = image.DecodeConfig(bytes.NewReader(raw))
+	}
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	var img image.Image
+	switch contentType {
+	case "image/webp":
+		img, err = webp.Decode(bytes.NewReader(raw))
+	default:
+		img, _, err = image.Decode(bytes.NewReader(raw))
+	}
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	// Phone cameras store rotation in EXIF, apply it before the tag is discarded
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(raw))
+	}
+
+	// PNG keeps transparency, everything else becomes JPEG
+	if contentType == "image/png" {
+		data, err := encodePNG(img)
+		if err != nil {
+			return nil, err
+		}
+		return &ProcessedImage{Image: img, Data: data, ContentType: "image/png", Ext: ".png"}, nil
+	}
+
+	data, err := encodeJPEG(img)
+	if err != nil {
+		return nil, err
+	}
+	return &ProcessedImage{Image: img, Data: data, ContentType: "image/jpeg", Ext: ".jpg"}, nil
+}
+
+// Thumbnail scales img down so its longest side is at most size pixels and encodes it as JPEG
+func Thumbnail(img image.Image, size int) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			h = h * size / w
+			w = size
+		} else {
+			w = w * size / h
+			h = size
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	// JPEG has no alpha, paint transparent areas white instead of black
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return encodeJPEG(dst)
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation returns the EXIF orientation tag (1-8) of a JPEG, or 1 when absent
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		segLen := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if segLen < 2 || pos+2+segLen > len(data) {
+			return 1
+		}
+		// Start of scan, no more metadata segments follow
+		if marker == 0xDA {
+			return 1
+		}
+		if marker == 0xE1 {
+			seg := data[pos+4 : pos+2+segLen]
+			if len(seg) > 6 && string(seg[:6]) == "Exif\x00\x00" {
+				return exifOrientation(seg[6:])
+			}
+		}
+		pos += 2 + segLen
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			v := int(order.Uint16(tiff[entry+8:]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation rotates/flips img so it displays upright without the EXIF tag
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	swap := orientation >= 5
+	dw, dh := w, h
+	if swap {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				dx, dy = x, h-1-y
+			case 5: // mirror horizontal and rotate 270 CW
+				dx, dy = y, x
+			case 6: // rotate 90 CW
+				dx, dy = h-1-y, x
+			case 7: // mirror horizontal and rotate 90 CW
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 270 CW
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// NormalizePhone converts an Indonesian phone number such as "0812-3456-7890", "62812 3456 7890"
+// or "+62 21 555 1234" to E.164 form (+6281234567890).
+func NormalizePhone(phone string) (string, error) {
+	var digits strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	// National significant number: without the country code or trunk prefix 0
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(number, "62"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		number = number[1:]
+	}
+
+	// Mobile numbers start with 8, landlines with an area code; both have 7 to 12 digits after the prefix
+	if len(number) < 7 || len(number) > 12 || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	return "+62" + number, nil
+}
@@ -26,6 +26,7 @@ class DashboardScreen extends StatefulWidget {
 class _DashboardScreenState extends State<DashboardScreen> {
   String userName = 'User';
   String userNamaSekolah = '';
+  String userSekolahDiminta = '';
   String? userPicture;
   bool isLoading = true;
   int _currentNavIndex = 0;
@@ -40,6 +41,13 @@ class _DashboardScreenState extends State<DashboardScreen> {
   
   String get baseUrl => dotenv.env['API_URL'] ?? 'http://10.0.2.2:8080';
 
+  // Sekolah yang sedang diminta tampil sampai admin atau koordinator menyetujuinya
+  String get _labelSekolah {
+    if (userSekolahDiminta.isEmpty) return userNamaSekolah;
+    if (userNamaSekolah.isEmpty) return '$userSekolahDiminta (menunggu persetujuan)';
+    return '$userNamaSekolah · pindah ke $userSekolahDiminta menunggu persetujuan';
+  }
+
   @override
   void initState() {
     super.initState();
@@ -60,6 +68,7 @@ class _DashboardScreenState extends State<DashboardScreen> {
           userData = result['user'];
           userName = result['user']['name'] ?? 'User';
           userNamaSekolah = result['user']['school_name'] ?? '';
+          userSekolahDiminta = result['user']['pending_school'] ?? '';
           userPicture = result['user']['picture'];
         }
         if (depositsResult['success']) {
@@ -312,7 +321,7 @@ class _DashboardScreenState extends State<DashboardScreen> {
                             Icon(Icons.apartment, size: 14, color: Colors.grey[600]),
                             const SizedBox(width: 4),
                             Text(
-                              userNamaSekolah,
+                              _labelSekolah,
                               style: TextStyle(
                                 fontFamily: 'PlusJakartaSans',
                                 fontSize: 12,
@@ -453,7 +462,7 @@ class _DashboardScreenState extends State<DashboardScreen> {
                         crossAxisAlignment: CrossAxisAlignment.start,
                         children: [
                           Text(
-                            userNamaSekolah,
+                            _labelSekolah,
                             style: const TextStyle(
                               fontFamily: 'PlusJakartaSans',
                               fontSize: 12,
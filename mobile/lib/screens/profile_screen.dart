@@ -22,6 +22,8 @@ class _ProfileScreenState extends State<ProfileScreen> {
   final _namaSekolahController = TextEditingController();
   final _emailController = TextEditingController();
   
+  String _namaSekolahAwal = '';
+  String _sekolahDiminta = '';
   String? _pictureUrl;
   File? _selectedImage;
   bool _isLoading = true;
@@ -53,7 +55,9 @@ class _ProfileScreenState extends State<ProfileScreen> {
         if (result['success']) {
           final user = result['user'];
           _nameController.text = user['name'] ?? '';
-          _namaSekolahController.text = user['school_name'] ?? '';
+          _namaSekolahAwal = user['school_name'] ?? '';
+          _sekolahDiminta = user['pending_school'] ?? '';
+          _namaSekolahController.text = _namaSekolahAwal;
           _emailController.text = user['email'] ?? '';
           _pictureUrl = user['picture'];
         }
@@ -160,7 +164,8 @@ class _ProfileScreenState extends State<ProfileScreen> {
     try {
       final result = await AuthService.updateProfile(
         name: _nameController.text,
-        namaSekolah: _namaSekolahController.text,
+        // Nama sekolah hanya dikirim saat berubah, karena server mencatatnya sebagai permintaan pindah sekolah
+        namaSekolah: _namaSekolahController.text.trim() != _namaSekolahAwal ? _namaSekolahController.text.trim() : null,
         photo: _selectedImage,
       );
 
@@ -335,6 +340,17 @@ class _ProfileScreenState extends State<ProfileScreen> {
                             controller: _namaSekolahController,
                             decoration: _inputDecoration('Masukkan nama sekolah'),
                           ),
+                          if (_sekolahDiminta.isNotEmpty) ...[
+                            const SizedBox(height: 8),
+                            Text(
+                              'Permintaan bergabung ke $_sekolahDiminta menunggu persetujuan koordinator sekolah atau admin',
+                              style: TextStyle(
+                                fontFamily: 'PlusJakartaSans',
+                                fontSize: 12,
+                                color: Colors.orange[800],
+                              ),
+                            ),
+                          ],
                           const SizedBox(height: 40),
                           
                           // Save Button